@@ -0,0 +1,138 @@
+package pulumiconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	// ErrInvalidByteSize is returned when a value can't be parsed as a byte size.
+	ErrInvalidByteSize = errors.New("invalid byte size")
+)
+
+// ByteSize is a number of bytes that is read from the config either as a plain number or as a
+// human readable string such as `10MB` or `1.5GiB`.
+type ByteSize int64
+
+// Byte size units. Decimal units use powers of 1000 and binary units use powers of 1024.
+const (
+	kilobyte = 1e3
+	megabyte = 1e6
+	gigabyte = 1e9
+	terabyte = 1e12
+	petabyte = 1e15
+	kibibyte = 1 << 10
+	mebibyte = 1 << 20
+	gibibyte = 1 << 30
+	tebibyte = 1 << 40
+	pebibyte = 1 << 50
+)
+
+// byteSizeUnit returns the size in bytes of the given upper case unit.
+func byteSizeUnit(unit string) (float64, bool) {
+	switch unit {
+	case "", "B":
+		return 1, true
+	case "KB":
+		return kilobyte, true
+	case "MB":
+		return megabyte, true
+	case "GB":
+		return gigabyte, true
+	case "TB":
+		return terabyte, true
+	case "PB":
+		return petabyte, true
+	case "KIB":
+		return kibibyte, true
+	case "MIB":
+		return mebibyte, true
+	case "GIB":
+		return gibibyte, true
+	case "TIB":
+		return tebibyte, true
+	case "PIB":
+		return pebibyte, true
+	}
+	return 0, false
+}
+
+// UnmarshalJSON decodes a JSON number of bytes or a human readable byte size string.
+func (b *ByteSize) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n int64
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidByteSize, data)
+		}
+		*b = ByteSize(n)
+		return nil
+	}
+
+	size, err := parseByteSize(s)
+	if err != nil {
+		return err
+	}
+	*b = size
+	return nil
+}
+
+// parseByteSize parses a human readable byte size such as `10MB` or `1.5GiB`.
+func parseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return !isDigit(r) && r != '.'
+	})
+	if i == -1 {
+		i = len(s)
+	}
+
+	number, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: `%s`", ErrInvalidByteSize, s)
+	}
+
+	unit, ok := byteSizeUnit(strings.ToUpper(strings.TrimSpace(s[i:])))
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown unit in `%s`", ErrInvalidByteSize, s)
+	}
+
+	// A float64 can't represent math.MaxInt64 exactly, it rounds up to 2^63 which is out of range.
+	size := number * unit
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("%w: `%s` is too large", ErrInvalidByteSize, s)
+	}
+	return ByteSize(size), nil
+}
+
+// byteSizeRange is a validator function that checks an integer byte count lies within the space
+// separated byte size bounds of the tag parameter, e.g. `bytesizerange=1MB 1GB`.
+func byteSizeRange(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.Int64 {
+		return true
+	}
+
+	lowerBound, upperBound, ok := strings.Cut(strings.TrimSpace(fl.Param()), " ")
+	if !ok {
+		return false
+	}
+	lower, err := parseByteSize(lowerBound)
+	if err != nil {
+		return false
+	}
+	upper, err := parseByteSize(upperBound)
+	if err != nil {
+		return false
+	}
+
+	size := ByteSize(field.Int())
+	return size >= lower && size <= upper
+}
@@ -0,0 +1,124 @@
+package pulumiconfig
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestByteSize struct {
+	MaxUploadSize ByteSize `json:"max_upload_size" validate:"bytesizerange=1MB 1GB"`
+}
+
+func Test_parseByteSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    ByteSize
+		wantErr bool
+	}{
+		{
+			name:  "decimal unit",
+			value: "10MB",
+			want:  10_000_000,
+		},
+		{
+			name:  "fractional binary unit",
+			value: "1.5GiB",
+			want:  1_610_612_736,
+		},
+		{
+			name:  "plain bytes",
+			value: "512",
+			want:  512,
+		},
+		{
+			name:    "maximum int64 rounds up out of range",
+			value:   "9223372036854775807",
+			wantErr: true,
+		},
+		{
+			name:    "too large",
+			value:   "9000PiB",
+			wantErr: true,
+		},
+		{
+			name:    "invalid unit",
+			value:   "10XB",
+			wantErr: true,
+		},
+		{
+			name:    "missing number",
+			value:   "MB",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseByteSize(tt.value)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidByteSize)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetConfigByteSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    ByteSize
+		wantErr bool
+	}{
+		{
+			name:  "size within range",
+			value: `"10MB"`,
+			want:  10_000_000,
+		},
+		{
+			name:  "number of bytes within range",
+			value: `2000000`,
+			want:  2_000_000,
+		},
+		{
+			name:  "lower bound is inclusive",
+			value: `"1MB"`,
+			want:  1_000_000,
+		},
+		{
+			name:    "size below range",
+			value:   `"999KB"`,
+			wantErr: true,
+		},
+		{
+			name:    "size above range",
+			value:   `"1GiB"`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid unit",
+			value:   `"10 parsecs"`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runWithConfig(t, map[string]string{
+				"project:max_upload_size": tt.value,
+			}, func(ctx *pulumi.Context) {
+				obj := &TestByteSize{}
+				err := GetConfig(ctx, obj)
+				if tt.wantErr {
+					assert.Error(t, err)
+				} else {
+					assert.NoError(t, err)
+					assert.Equal(t, tt.want, obj.MaxUploadSize)
+				}
+			})
+		})
+	}
+}
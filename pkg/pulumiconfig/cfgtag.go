@@ -0,0 +1,113 @@
+package pulumiconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+var (
+	// ErrInvalidCfgTag is returned when a `cfg` tag can't be parsed.
+	ErrInvalidCfgTag = errors.New("invalid cfg tag")
+)
+
+// cfgTag holds the components of a combined `cfg` tag, e.g.
+// `cfg:"key=org_id,env=ORG_ID,default=0,required"`.
+type cfgTag struct {
+	key          string // The Pulumi config key.
+	env          string // The `|` separated environment variables used when the config key is not set.
+	defaultValue string // The value used when neither the config key nor the environment variable is set.
+	required     bool   // Whether a value must be found.
+}
+
+// parseCfgTag parses a combined `cfg` tag. The `key` component is mandatory.
+func parseCfgTag(tag string) (cfgTag, error) {
+	var parsed cfgTag
+	for _, component := range strings.Split(tag, ",") {
+		name, value, hasValue := strings.Cut(component, "=")
+		switch {
+		case name == "required" && !hasValue:
+			parsed.required = true
+		case name == "key" && hasValue:
+			parsed.key = value
+		case name == "env" && hasValue:
+			parsed.env = value
+		case name == "default" && hasValue:
+			parsed.defaultValue = value
+		default:
+			return cfgTag{}, fmt.Errorf("%w: unknown component `%s` in `%s`", ErrInvalidCfgTag, component, tag)
+		}
+	}
+	if parsed.key == "" {
+		return cfgTag{}, fmt.Errorf("%w: missing key in `%s`", ErrInvalidCfgTag, tag)
+	}
+	return parsed, nil
+}
+
+// getCfgValue populates a field with a `cfg` tag. The Pulumi config key is used when set, then the
+// value of the config file of WithConfigFile, then the first environment variable that is set and
+// finally the default value. Fields tagged `frozen:"true"` skip the config file and the environment.
+// A missing value is only an error for required fields. Values are decoded with decodeFieldValue, so
+// all tags that control the decoding of a field apply to `cfg` fields as well.
+func getCfgValue(ctx *pulumi.Context, opts *options, fieldType reflect.StructField, field reflect.Value, tagValue string) error {
+	tag, err := parseCfgTag(tagValue)
+	if err != nil {
+		return fmt.Errorf("field `%s`: %w", fieldType.Name, err)
+	}
+
+	cfg := config.New(ctx, fieldType.Tag.Get("pulumiConfigNamespace"))
+	raw, err := cfg.Try(tag.key)
+	// Frozen fields only come from the Pulumi config, never from the config file or the environment.
+	var env, fileRaw string
+	var inFile bool
+	if fieldType.Tag.Get("frozen") != "true" {
+		env = opts.lookupEnv(tag.env)
+		fileRaw, inFile = opts.fileValue(ctx, fieldType.Tag, tag.key)
+	}
+	switch {
+	case err == nil:
+	case inFile:
+		raw = fileRaw
+	case env != "":
+		raw = quotePlainValue(env, field.Type())
+	case tag.defaultValue != "":
+		raw = quotePlainValue(tag.defaultValue, field.Type())
+	case !tag.required:
+		return nil
+	default:
+		return &MissingRequiredFieldError{Field: tag.key, Err: err}
+	}
+
+	if err := decodeFieldValue(ctx, raw, field, fieldType.Tag); err != nil {
+		return fmt.Errorf("Error while reading pulumi config `%s`: %w", tag.key, err)
+	}
+	return nil
+}
+
+// lookupEnv returns the value of the first of the `|` separated environment variables, e.g.
+// `NEW_TOKEN|OLD_TOKEN`, that is set and non-empty. The prefix of WithEnvPrefix is prepended to every
+// variable, falling back to the unprefixed variable only if WithUnprefixedEnvFallback is passed.
+func (o *options) lookupEnv(names string) string {
+	if names == "" {
+		return ""
+	}
+	for _, name := range strings.Split(names, "|") {
+		if o.envPrefix != "" {
+			if value := os.Getenv(o.envPrefix + name); value != "" {
+				return value
+			}
+			if !o.envPrefixFallback {
+				continue
+			}
+		}
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,311 @@
+package pulumiconfig
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/internals"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestCfgTag struct {
+	OrgID   int    `cfg:"key=org_id,env=TEST_ORG_ID,default=7,required"`
+	Region  string `cfg:"key=region,env=TEST_REGION"`
+	Name    string `cfg:"key=name,required"`
+	Comment string `cfg:"key=comment"`
+}
+
+type TestCfgTagPointers struct {
+	SubscriptionID *string `cfg:"key=subscription_id,env=TEST_SUB_ID"`
+	Replicas       *int    `cfg:"key=replicas,env=TEST_REPLICAS"`
+	Enabled        *bool   `cfg:"key=enabled,env=TEST_ENABLED"`
+}
+
+type TestCfgTagEnvFallback struct {
+	Token string `cfg:"key=token,env=TEST_NEW_TOKEN|TEST_OLD_TOKEN"`
+}
+
+type TestCfgTagFrozen struct {
+	Region string `cfg:"key=region,env=TEST_REGION,default=eu-west-1" frozen:"true"`
+}
+
+type TestInvalidCfgTag struct {
+	Name string `cfg:"name,required"`
+}
+
+func Test_parseCfgTag(t *testing.T) {
+	tag, err := parseCfgTag("key=org_id,env=ORG_ID,default=0,required")
+	assert.NoError(t, err)
+	assert.Equal(t, cfgTag{key: "org_id", env: "ORG_ID", defaultValue: "0", required: true}, tag)
+
+	_, err = parseCfgTag("env=ORG_ID")
+	assert.ErrorIs(t, err, ErrInvalidCfgTag)
+
+	_, err = parseCfgTag("key=org_id,secret")
+	assert.ErrorIs(t, err, ErrInvalidCfgTag)
+}
+
+func TestGetConfigCfgTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]string
+		env     map[string]string
+		want    *TestCfgTag
+		wantErr string
+	}{
+		{
+			name: "config key",
+			config: map[string]string{
+				"project:org_id": `123`,
+				"project:region": `"eu-west-1"`,
+				"project:name":   `"DeploymentName"`,
+			},
+			env:  map[string]string{"TEST_ORG_ID": "456", "TEST_REGION": "us-east-1"},
+			want: &TestCfgTag{OrgID: 123, Region: "eu-west-1", Name: "DeploymentName"},
+		},
+		{
+			name: "env fallback",
+			config: map[string]string{
+				"project:name": `"DeploymentName"`,
+			},
+			env:  map[string]string{"TEST_ORG_ID": "456", "TEST_REGION": "us-east-1"},
+			want: &TestCfgTag{OrgID: 456, Region: "us-east-1", Name: "DeploymentName"},
+		},
+		{
+			name: "default fallback",
+			config: map[string]string{
+				"project:name": `"DeploymentName"`,
+			},
+			want: &TestCfgTag{OrgID: 7, Name: "DeploymentName"},
+		},
+		{
+			name:    "required value is missing",
+			config:  map[string]string{},
+			wantErr: "pulumi config `name`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_ORG_ID", tt.env["TEST_ORG_ID"])
+			t.Setenv("TEST_REGION", tt.env["TEST_REGION"])
+
+			runWithConfig(t, tt.config, func(ctx *pulumi.Context) {
+				obj := &TestCfgTag{}
+				err := GetConfig(ctx, obj)
+				if tt.wantErr != "" {
+					assert.ErrorContains(t, err, tt.wantErr)
+				} else {
+					assert.NoError(t, err)
+					assert.Equal(t, tt.want, obj)
+				}
+			})
+		})
+	}
+}
+
+func TestGetConfigCfgTagPointers(t *testing.T) {
+	replicas := 3
+	tests := []struct {
+		name   string
+		config map[string]string
+		env    map[string]string
+		want   *TestCfgTagPointers
+	}{
+		{
+			name:   "unset pointers stay nil",
+			config: map[string]string{},
+			want:   &TestCfgTagPointers{},
+		},
+		{
+			name:   "environment fallback",
+			config: map[string]string{},
+			env:    map[string]string{"TEST_SUB_ID": "sub-123", "TEST_REPLICAS": "3", "TEST_ENABLED": "false"},
+			want:   &TestCfgTagPointers{SubscriptionID: stringPtr("sub-123"), Replicas: &replicas, Enabled: boolPtr(false)},
+		},
+		{
+			name: "config takes precedence over environment",
+			config: map[string]string{
+				"project:subscription_id": `"sub-config"`,
+				"project:enabled":         `true`,
+			},
+			env:  map[string]string{"TEST_SUB_ID": "sub-123", "TEST_REPLICAS": "3", "TEST_ENABLED": "false"},
+			want: &TestCfgTagPointers{SubscriptionID: stringPtr("sub-config"), Replicas: &replicas, Enabled: boolPtr(true)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_SUB_ID", tt.env["TEST_SUB_ID"])
+			t.Setenv("TEST_REPLICAS", tt.env["TEST_REPLICAS"])
+			t.Setenv("TEST_ENABLED", tt.env["TEST_ENABLED"])
+
+			runWithConfig(t, tt.config, func(ctx *pulumi.Context) {
+				obj := &TestCfgTagPointers{}
+				assert.NoError(t, GetConfig(ctx, obj))
+				assert.Equal(t, tt.want, obj)
+			})
+		})
+	}
+}
+
+func TestGetConfigCfgTagEnvFallback(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{
+			name: "only the second variable is set",
+			env:  map[string]string{"TEST_OLD_TOKEN": "old"},
+			want: "old",
+		},
+		{
+			name: "first variable wins",
+			env:  map[string]string{"TEST_NEW_TOKEN": "new", "TEST_OLD_TOKEN": "old"},
+			want: "new",
+		},
+		{
+			name: "no variable is set",
+			env:  map[string]string{},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_NEW_TOKEN", tt.env["TEST_NEW_TOKEN"])
+			t.Setenv("TEST_OLD_TOKEN", tt.env["TEST_OLD_TOKEN"])
+
+			runWithConfig(t, map[string]string{}, func(ctx *pulumi.Context) {
+				obj := &TestCfgTagEnvFallback{}
+				assert.NoError(t, GetConfig(ctx, obj))
+				assert.Equal(t, tt.want, obj.Token)
+			})
+		})
+	}
+}
+
+func TestGetConfigCfgTagEnvPrefix(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		opts []Validator
+		want string
+	}{
+		{
+			name: "prefixed variable is present",
+			env:  map[string]string{"TENANTA_TEST_NEW_TOKEN": "tenant", "TEST_NEW_TOKEN": "global"},
+			opts: []Validator{WithEnvPrefix("TENANTA_")},
+			want: "tenant",
+		},
+		{
+			name: "prefixed variable is absent without fallback",
+			env:  map[string]string{"TEST_NEW_TOKEN": "global"},
+			opts: []Validator{WithEnvPrefix("TENANTA_")},
+			want: "",
+		},
+		{
+			name: "prefixed variable is absent with fallback",
+			env:  map[string]string{"TEST_NEW_TOKEN": "global"},
+			opts: []Validator{WithEnvPrefix("TENANTA_"), WithUnprefixedEnvFallback()},
+			want: "global",
+		},
+		{
+			name: "no prefix",
+			env:  map[string]string{"TENANTA_TEST_NEW_TOKEN": "tenant", "TEST_NEW_TOKEN": "global"},
+			want: "global",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TENANTA_TEST_NEW_TOKEN", tt.env["TENANTA_TEST_NEW_TOKEN"])
+			t.Setenv("TEST_NEW_TOKEN", tt.env["TEST_NEW_TOKEN"])
+			t.Setenv("TEST_OLD_TOKEN", "")
+
+			runWithConfig(t, map[string]string{}, func(ctx *pulumi.Context) {
+				obj := &TestCfgTagEnvFallback{}
+				assert.NoError(t, GetConfig(ctx, obj, tt.opts...))
+				assert.Equal(t, tt.want, obj.Token)
+			})
+		})
+	}
+}
+
+func TestGetConfigCfgTagFrozen(t *testing.T) {
+	t.Setenv("TEST_REGION", "us-east-1")
+
+	runWithConfig(t, map[string]string{}, func(ctx *pulumi.Context) {
+		obj := &TestCfgTagFrozen{}
+		assert.NoError(t, GetConfig(ctx, obj))
+		assert.Equal(t, "eu-west-1", obj.Region, "The environment must not change a frozen field")
+
+		obj = &TestCfgTagFrozen{}
+		assert.NoError(t, GetConfig(ctx, obj, WithConfigFile(writeConfigFile(t, `{"region": "fromfile"}`))))
+		assert.Equal(t, "eu-west-1", obj.Region, "The config file must not change a frozen field")
+
+		type frozenJSON struct {
+			Region string `json:"region" frozen:"true"`
+		}
+		jsonObj := &frozenJSON{}
+		assert.NoError(t, GetConfig(ctx, jsonObj, WithConfigFile(writeConfigFile(t, `{"region": "fromfile"}`))))
+		assert.Empty(t, jsonObj.Region, "The config file must not set a frozen field without a cfg tag")
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:region": `"us-west-1"`,
+	}, func(ctx *pulumi.Context) {
+		obj := &TestCfgTagFrozen{}
+		assert.NoError(t, GetConfig(ctx, obj))
+		assert.Equal(t, "us-west-1", obj.Region)
+	})
+}
+
+func TestGetConfigCfgTagDecoding(t *testing.T) {
+	type config struct {
+		PollInterval *time.Duration      `cfg:"key=poll_interval,env=TEST_POLL_INTERVAL"`
+		Timeout      time.Duration       `cfg:"key=timeout,env=TEST_TIMEOUT" unit:"s"`
+		Zones        []string            `cfg:"key=zones,env=TEST_ZONES" configFormat:"csv"`
+		Ports        []int               `cfg:"key=ports,default=80" flexlist:"true"`
+		StartsAt     time.Time           `cfg:"key=starts_at,env=TEST_STARTS_AT"`
+		Password     pulumi.StringOutput `cfg:"key=password,env=TEST_PASSWORD" secret:"true"`
+	}
+
+	t.Setenv("TEST_POLL_INTERVAL", "5s")
+	t.Setenv("TEST_TIMEOUT", "30")
+	t.Setenv("TEST_ZONES", "a, b,c")
+	t.Setenv("TEST_STARTS_AT", "2024-03-01T22:00:00Z")
+	t.Setenv("TEST_PASSWORD", "p4ssw0rd")
+
+	runWithConfig(t, map[string]string{}, func(ctx *pulumi.Context) {
+		obj := &config{}
+		assert.NoError(t, GetConfig(ctx, obj))
+		assert.Equal(t, durationPtr(5*time.Second), obj.PollInterval)
+		assert.Equal(t, 30*time.Second, obj.Timeout)
+		assert.Equal(t, []string{"a", "b", "c"}, obj.Zones)
+		assert.Equal(t, []int{80}, obj.Ports)
+		assert.Equal(t, time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC), obj.StartsAt)
+
+		result, err := internals.UnsafeAwaitOutput(context.Background(), obj.Password)
+		assert.NoError(t, err)
+		assert.Equal(t, "p4ssw0rd", result.Value)
+		assert.True(t, result.Secret)
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:zones": `"x,y"`,
+		"project:ports": `[443, 8443]`,
+	}, func(ctx *pulumi.Context) {
+		obj := &config{}
+		assert.NoError(t, GetConfig(ctx, obj))
+		assert.Equal(t, []string{"x", "y"}, obj.Zones)
+		assert.Equal(t, []int{443, 8443}, obj.Ports)
+	})
+}
+
+func TestGetConfigInvalidCfgTag(t *testing.T) {
+	runWithConfig(t, map[string]string{}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &TestInvalidCfgTag{})
+		assert.ErrorIs(t, err, ErrInvalidCfgTag)
+		assert.ErrorContains(t, err, "field `Name`")
+	})
+}
@@ -0,0 +1,59 @@
+package pulumiconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// WithConfigFile reads config values that are missing from the Pulumi config from a JSON file, e.g.
+// for local development outside Pulumi. The file holds an object mapping config keys to their values,
+// using `namespace:key` keys like the Pulumi config or bare keys for the project namespace:
+//
+//	{"region": "eu-west-1", "provider:provider_credentials": {"token": "local"}}
+//
+// Values from the file take precedence over environment variables and defaults.
+func WithConfigFile(path string) Option {
+	return func(o *options) {
+		o.configFile = path
+	}
+}
+
+// loadConfigFile parses the config file of WithConfigFile, if any.
+func (o *options) loadConfigFile() error {
+	if o.configFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(o.configFile) //nolint:gosec // the path is provided by the program itself
+	if err != nil {
+		return fmt.Errorf("Error while reading config file `%s`: %w", o.configFile, err)
+	}
+	if err := json.Unmarshal(data, &o.fileConfig); err != nil {
+		return fmt.Errorf("Error while reading config file `%s`: %w", o.configFile, err)
+	}
+	return nil
+}
+
+// fileValue returns the raw JSON value of the config key from the config file, looking up the key in
+// the namespace of the field's `pulumiConfigNamespace` tag or in the project namespace.
+func (o *options) fileValue(ctx *pulumi.Context, tag reflect.StructTag, key string) (string, bool) {
+	if o.fileConfig == nil {
+		return "", false
+	}
+
+	namespace := tag.Get("pulumiConfigNamespace")
+	if namespace == "" {
+		namespace = ctx.Project()
+	}
+	if raw, ok := o.fileConfig[namespace+":"+key]; ok {
+		return string(raw), true
+	}
+	if raw, ok := o.fileConfig[key]; ok && namespace == ctx.Project() {
+		return string(raw), true
+	}
+	return "", false
+}
@@ -0,0 +1,88 @@
+package pulumiconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestConfigFile struct {
+	DigitalOcean        TestDigitalOcean         `json:"digital_ocean" validate:"required"`
+	ProviderCredentials *TestProviderCredentials `json:"provider_credentials" pulumiConfigNamespace:"provider"`
+	Name                string                   `json:"name"`
+	OrgID               int                      `cfg:"key=org_id,env=TEST_ORG_ID,default=7"`
+}
+
+// writeConfigFile writes the content to a config file in a temporary directory and returns its path.
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.local.json")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestWithConfigFile(t *testing.T) {
+	path := writeConfigFile(t, `{
+		"digital_ocean": {"region": "eu-west-1"},
+		"provider:provider_credentials": {"token": "local"},
+		"name": "LocalName",
+		"org_id": 42
+	}`)
+
+	tests := []struct {
+		name   string
+		config map[string]string
+		env    string
+		want   *TestConfigFile
+	}{
+		{
+			name:   "file supplies missing values",
+			config: map[string]string{},
+			env:    "13",
+			want: &TestConfigFile{
+				DigitalOcean:        TestDigitalOcean{Region: "eu-west-1"},
+				ProviderCredentials: &TestProviderCredentials{Token: "local"},
+				Name:                "LocalName",
+				OrgID:               42,
+			},
+		},
+		{
+			name: "config shadows the file",
+			config: map[string]string{
+				"project:digital_ocean": `{"region":"us-east-1"}`,
+				"project:name":          `"DeploymentName"`,
+				"project:org_id":        `3`,
+			},
+			want: &TestConfigFile{
+				DigitalOcean:        TestDigitalOcean{Region: "us-east-1"},
+				ProviderCredentials: &TestProviderCredentials{Token: "local"},
+				Name:                "DeploymentName",
+				OrgID:               3,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_ORG_ID", tt.env)
+			runWithConfig(t, tt.config, func(ctx *pulumi.Context) {
+				obj := &TestConfigFile{}
+				assert.NoError(t, GetConfig(ctx, obj, WithConfigFile(path)))
+				assert.Equal(t, tt.want, obj)
+			})
+		})
+	}
+}
+
+func TestWithConfigFileInvalid(t *testing.T) {
+	runWithConfig(t, map[string]string{}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &TestConfigFile{}, WithConfigFile(filepath.Join(t.TempDir(), "missing.json")))
+		assert.ErrorContains(t, err, "Error while reading config file")
+		assert.ErrorIs(t, err, os.ErrNotExist)
+
+		err = GetConfig(ctx, &TestConfigFile{}, WithConfigFile(writeConfigFile(t, `region: eu-west-1`)))
+		assert.ErrorContains(t, err, "Error while reading config file")
+	})
+}
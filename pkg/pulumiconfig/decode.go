@@ -0,0 +1,281 @@
+package pulumiconfig
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FieldDecoder decodes a raw JSON config value into the provided field.
+type FieldDecoder func(raw []byte, field reflect.Value) error
+
+//nolint:gochecknoglobals // registry shared by all GetConfig calls
+var (
+	fieldDecodersMu sync.RWMutex
+	fieldDecoders   = map[reflect.Type]FieldDecoder{}
+)
+
+// RegisterFieldDecoder registers a decoder used for config fields of the given type instead of
+// the default JSON decoding. Registering a decoder for a type that already has one replaces it.
+func RegisterFieldDecoder(t reflect.Type, decoder FieldDecoder) {
+	fieldDecodersMu.Lock()
+	defer fieldDecodersMu.Unlock()
+	fieldDecoders[t] = decoder
+}
+
+// RegisterDefaultImpl registers the concrete implementation used for config fields of the given
+// interface type. The factory must return a pointer to a new value implementing the interface,
+// into which the config value is decoded as JSON.
+func RegisterDefaultImpl(t reflect.Type, factory func() interface{}) {
+	RegisterFieldDecoder(t, func(raw []byte, field reflect.Value) error {
+		impl := factory()
+		if !reflect.TypeOf(impl).Implements(t) {
+			return fmt.Errorf("default implementation `%T` of `%s`: %w", impl, t, ErrUnsupportedFieldType)
+		}
+		if err := json.Unmarshal(raw, impl); err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(impl))
+		return nil
+	})
+}
+
+// getFieldDecoder returns the decoder registered for the given type, if any.
+func getFieldDecoder(t reflect.Type) (FieldDecoder, bool) {
+	fieldDecodersMu.RLock()
+	defer fieldDecodersMu.RUnlock()
+	decoder, ok := fieldDecoders[t]
+	return decoder, ok
+}
+
+// decodeConfigValue decodes the raw JSON config value into the provided field.
+// A decoder registered for the field's type takes precedence over the built-in decoding.
+func decodeConfigValue(raw string, field reflect.Value) error {
+	if decoder, ok := getFieldDecoder(field.Type()); ok {
+		return decoder([]byte(raw), field)
+	}
+
+	if isDurationType(field.Type()) {
+		return decodeDuration(raw, field, "")
+	}
+
+	// Types with their own JSON decoding are always decoded as JSON.
+	if _, ok := field.Addr().Interface().(json.Unmarshaler); ok {
+		return json.Unmarshal([]byte(raw), field.Addr().Interface())
+	}
+
+	switch field.Kind() { //nolint:exhaustive // all other kinds are decoded as JSON
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return decodeInteger(raw, field)
+	default:
+		return json.Unmarshal([]byte(raw), field.Addr().Interface())
+	}
+}
+
+// decodePlainValue decodes a value that is not JSON encoded, such as an environment variable, into the
+// provided field. See quotePlainValue for how the value is interpreted.
+func decodePlainValue(value string, field reflect.Value) error {
+	return decodeConfigValue(quotePlainValue(value, field.Type()), field)
+}
+
+// quotePlainValue turns a value that is not JSON encoded, such as an environment variable, into a raw
+// JSON config value for a field of type t. String and time values are quoted verbatim, duration
+// values are quoted unless they are numbers, and any other value is used as JSON.
+func quotePlainValue(value string, t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t.Kind() == reflect.String, isTimeType(t):
+		return strconv.Quote(value)
+	case isDurationType(t) && !json.Valid([]byte(value)):
+		return strconv.Quote(value)
+	default:
+		return value
+	}
+}
+
+// decodeInteger decodes a raw JSON number into an integer field without a float64 round-trip,
+// so large values are preserved exactly.
+func decodeInteger(raw string, field reflect.Value) error {
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+
+	number, ok := value.(json.Number)
+	if !ok {
+		return &json.UnmarshalTypeError{Value: reflect.TypeOf(value).String(), Type: field.Type()}
+	}
+
+	if field.Kind() >= reflect.Uint && field.Kind() <= reflect.Uint64 {
+		u, err := strconv.ParseUint(number.String(), 10, 64)
+		if err != nil || field.OverflowUint(u) {
+			return &json.UnmarshalTypeError{Value: "number " + number.String(), Type: field.Type()}
+		}
+		field.SetUint(u)
+		return nil
+	}
+
+	i, err := number.Int64()
+	if err != nil || field.OverflowInt(i) {
+		return &json.UnmarshalTypeError{Value: "number " + number.String(), Type: field.Type()}
+	}
+	field.SetInt(i)
+	return nil
+}
+
+// isDurationType reports whether t is time.Duration.
+func isDurationType(t reflect.Type) bool {
+	return t == reflect.TypeOf(time.Duration(0))
+}
+
+// decodeDuration decodes a raw JSON value into a duration field. Strings are parsed with
+// time.ParseDuration, numbers are interpreted in the given unit (e.g. `ms` or `s`) or as
+// nanoseconds when no unit is given. Fractional numbers are only supported with a unit.
+func decodeDuration(raw string, field reflect.Value, unit string) error {
+	var s string
+	if json.Unmarshal([]byte(raw), &s) == nil {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	if unit == "" {
+		return decodeInteger(raw, field)
+	}
+
+	unitDuration, err := time.ParseDuration("1" + unit)
+	if err != nil {
+		return fmt.Errorf("invalid duration unit `%s`: %w", unit, err)
+	}
+
+	var number float64
+	if err := json.Unmarshal([]byte(raw), &number); err != nil {
+		return err
+	}
+
+	// A float64 can't represent math.MaxInt64 exactly, it rounds up to 2^63 which is out of range.
+	d := number * float64(unitDuration)
+	if math.IsNaN(d) || d < math.MinInt64 || d >= math.MaxInt64 {
+		return fmt.Errorf("%w: `%s%s` is out of range", ErrInvalidDuration, raw, unit)
+	}
+	field.SetInt(int64(d))
+	return nil
+}
+
+// isTimeType reports whether t is time.Time.
+func isTimeType(t reflect.Type) bool {
+	return t == reflect.TypeOf(time.Time{})
+}
+
+// decodeTime decodes a raw config value into a time field using the given layout, or RFC3339 when
+// no layout is given. Both JSON strings and plain strings are accepted.
+func decodeTime(raw string, field reflect.Value, layout string) error {
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	var s string
+	if err := json.Unmarshal([]byte(raw), &s); err != nil {
+		s = raw
+	}
+
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return fmt.Errorf("%w: `%s` does not match the layout `%s`", ErrInvalidTime, s, layout)
+	}
+	field.Set(reflect.ValueOf(t))
+	return nil
+}
+
+// decodeEncoded decodes a raw config value with the given encoding into a string or byte slice field.
+// The only supported encoding is `gzip+base64`, a base64 encoded gzip compressed value.
+func decodeEncoded(raw string, field reflect.Value, encoding string) error {
+	if encoding != "gzip+base64" {
+		return fmt.Errorf("%w: unsupported encoding `%s`", ErrInvalidEncoding, encoding)
+	}
+
+	var s string
+	if err := json.Unmarshal([]byte(raw), &s); err != nil {
+		s = raw
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+	}
+	defer reader.Close() //nolint:errcheck // closing a reader of an in-memory buffer never fails
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+	}
+
+	switch {
+	case field.Kind() == reflect.String:
+		field.SetString(string(data))
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.Uint8:
+		field.SetBytes(data)
+	default:
+		return fmt.Errorf("field of type `%s`: %w", field.Type(), ErrUnsupportedFieldType)
+	}
+	return nil
+}
+
+// decodeCSV decodes a comma separated string into a slice field, converting every element to the
+// slice's element type. JSON arrays are decoded as usual.
+func decodeCSV(raw string, field reflect.Value) error {
+	if strings.HasPrefix(strings.TrimSpace(raw), "[") {
+		return decodeConfigValue(raw, field)
+	}
+
+	var s string
+	if err := json.Unmarshal([]byte(raw), &s); err != nil {
+		s = raw
+	}
+
+	// An empty value is an empty list rather than a list with a single empty element.
+	if strings.TrimSpace(s) == "" {
+		field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+		return nil
+	}
+
+	parts := strings.Split(s, ",")
+	slice := reflect.MakeSlice(field.Type(), len(parts), len(parts))
+	for i, part := range parts {
+		if err := decodePlainValue(strings.TrimSpace(part), slice.Index(i)); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	field.Set(slice)
+	return nil
+}
+
+// wrapScalar wraps a raw JSON value that is not an array into a single-element JSON array.
+func wrapScalar(raw string) string {
+	trimmed := strings.TrimSpace(raw)
+	if strings.HasPrefix(trimmed, "[") || trimmed == "null" {
+		return raw
+	}
+	return "[" + trimmed + "]"
+}
@@ -0,0 +1,452 @@
+package pulumiconfig
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+// Color is a custom type decoded from a hex string such as "#ff0000".
+type Color struct {
+	R, G, B uint8
+}
+
+type TestColor struct {
+	Background Color `json:"background"`
+}
+
+type TestFlexList struct {
+	Zones []string `json:"zones" flexlist:"true"`
+}
+
+type TestDurationUnit struct {
+	TimeoutMs time.Duration `json:"timeout_ms" unit:"ms"`
+	Timeout   time.Duration `json:"timeout" unit:"s"`
+}
+
+// Backend is an interface populated through its registered default implementation.
+type Backend interface {
+	URL() string
+}
+
+// TestS3Backend is the default Backend implementation.
+type TestS3Backend struct {
+	Bucket string `json:"bucket" validate:"required"`
+}
+
+// URL returns the S3 URL of the bucket.
+func (b *TestS3Backend) URL() string {
+	return "s3://" + b.Bucket
+}
+
+type TestBackend struct {
+	Backend Backend `json:"backend"`
+}
+
+type TestDuration struct {
+	Timeout      time.Duration  `json:"timeout" validate:"required"`
+	PollInterval *time.Duration `json:"poll_interval"`
+	Retry        time.Duration  `json:"retry" validate:"default=30s"`
+}
+
+type TestTime struct {
+	WindowStart time.Time  `json:"window_start"`
+	WindowEnd   *time.Time `json:"window_end"`
+	ReleaseDate time.Time  `json:"release_date" timeLayout:"2006-01-02"`
+}
+
+type TestEncoded struct {
+	Policy   string `json:"policy" encoding:"gzip+base64"`
+	Template []byte `json:"template" encoding:"gzip+base64"`
+}
+
+type TestCSV struct {
+	Regions []string  `json:"regions" configFormat:"csv"`
+	Ports   []int     `json:"ports" configFormat:"csv"`
+	Weights []float64 `json:"weights" configFormat:"csv"`
+}
+
+// decodeColor decodes a JSON hex color string into a Color field.
+func decodeColor(raw []byte, field reflect.Value) error {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return err
+	}
+
+	var c Color
+	if _, err := fmt.Sscanf(s, "#%02x%02x%02x", &c.R, &c.G, &c.B); err != nil {
+		return fmt.Errorf("invalid color %q: %w", s, err)
+	}
+	field.Set(reflect.ValueOf(c))
+	return nil
+}
+
+// restoreFieldDecoders resets the field decoder registry to its current state when the test ends,
+// so decoders registered by the test don't leak into other tests.
+func restoreFieldDecoders(t *testing.T) {
+	fieldDecodersMu.RLock()
+	saved := make(map[reflect.Type]FieldDecoder, len(fieldDecoders))
+	for typ, decoder := range fieldDecoders {
+		saved[typ] = decoder
+	}
+	fieldDecodersMu.RUnlock()
+
+	t.Cleanup(func() {
+		fieldDecodersMu.Lock()
+		defer fieldDecodersMu.Unlock()
+		fieldDecoders = saved
+	})
+}
+
+func TestRegisterFieldDecoder(t *testing.T) {
+	restoreFieldDecoders(t)
+	RegisterFieldDecoder(reflect.TypeOf(Color{}), decodeColor)
+
+	runWithConfig(t, map[string]string{
+		"project:background": `"#ff0000"`,
+	}, func(ctx *pulumi.Context) {
+		obj := &TestColor{}
+		assert.NoError(t, GetConfig(ctx, obj))
+		assert.Equal(t, Color{R: 255}, obj.Background)
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:background": `"red"`,
+	}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &TestColor{})
+		assert.ErrorContains(t, err, "pulumi config `background`")
+	})
+}
+
+func TestGetConfigFlexList(t *testing.T) {
+	tests := []struct {
+		name  string
+		zones string
+		want  []string
+	}{
+		{
+			name:  "scalar value",
+			zones: `"us-east-1a"`,
+			want:  []string{"us-east-1a"},
+		},
+		{
+			name:  "list value",
+			zones: `["us-east-1a","us-east-1b"]`,
+			want:  []string{"us-east-1a", "us-east-1b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runWithConfig(t, map[string]string{
+				"project:zones": tt.zones,
+			}, func(ctx *pulumi.Context) {
+				obj := &TestFlexList{}
+				assert.NoError(t, GetConfig(ctx, obj))
+				assert.Equal(t, tt.want, obj.Zones)
+			})
+		})
+	}
+}
+
+func TestGetConfigDurationUnit(t *testing.T) {
+	runWithConfig(t, map[string]string{
+		"project:timeout_ms": `500`,
+		"project:timeout":    `500`,
+	}, func(ctx *pulumi.Context) {
+		obj := &TestDurationUnit{}
+		assert.NoError(t, GetConfig(ctx, obj))
+		assert.Equal(t, 500*time.Millisecond, obj.TimeoutMs)
+		assert.Equal(t, 500*time.Second, obj.Timeout)
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:timeout": `"500 seconds"`,
+	}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &TestDurationUnit{})
+		assert.ErrorContains(t, err, "pulumi config `timeout`")
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:timeout_ms": `1e30`,
+		"project:timeout":    `-1e30`,
+	}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &TestDurationUnit{})
+		assert.ErrorIs(t, err, ErrInvalidDuration)
+		assert.ErrorContains(t, err, "pulumi config `timeout_ms`")
+		assert.ErrorContains(t, err, "pulumi config `timeout`")
+	})
+}
+
+func TestGetConfigDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]string
+		want    *TestDuration
+		wantErr string
+	}{
+		{
+			name: "duration strings",
+			config: map[string]string{
+				"project:timeout":       `"1h30m"`,
+				"project:poll_interval": `"5m"`,
+			},
+			want: &TestDuration{Timeout: 90 * time.Minute, PollInterval: durationPtr(5 * time.Minute), Retry: 30 * time.Second},
+		},
+		{
+			name: "bare integers are nanoseconds",
+			config: map[string]string{
+				"project:timeout":       `1500`,
+				"project:poll_interval": `2000`,
+				"project:retry":         `1000000000`,
+			},
+			want: &TestDuration{Timeout: 1500, PollInterval: durationPtr(2000), Retry: time.Second},
+		},
+		{
+			name: "default is applied to missing duration",
+			config: map[string]string{
+				"project:timeout": `"10s"`,
+			},
+			want: &TestDuration{Timeout: 10 * time.Second, Retry: 30 * time.Second},
+		},
+		{
+			name: "invalid duration string",
+			config: map[string]string{
+				"project:timeout": `"soon"`,
+			},
+			wantErr: "pulumi config `timeout`: time: invalid duration \"soon\"",
+		},
+		{
+			name: "invalid pointer duration string",
+			config: map[string]string{
+				"project:timeout":       `"10s"`,
+				"project:poll_interval": `"often"`,
+			},
+			wantErr: "pulumi config `poll_interval`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runWithConfig(t, tt.config, func(ctx *pulumi.Context) {
+				obj := &TestDuration{}
+				err := GetConfig(ctx, obj)
+				if tt.wantErr != "" {
+					assert.ErrorContains(t, err, tt.wantErr)
+				} else {
+					assert.NoError(t, err)
+					assert.Equal(t, tt.want, obj)
+				}
+			})
+		})
+	}
+}
+
+// durationPtr is a utility function to convert a duration into a pointer for easier comparison in tests.
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
+
+func TestGetConfigRequiredPointers(t *testing.T) {
+	type config struct {
+		PollInterval *time.Duration `json:"poll_interval" validate:"required"`
+		StartsAt     *time.Time     `json:"starts_at" validate:"required"`
+	}
+
+	runWithConfig(t, map[string]string{}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &config{})
+
+		var missingErr *MissingRequiredFieldError
+		assert.ErrorAs(t, err, &missingErr, "Missing pointers are reported like any other missing field")
+		assert.ErrorContains(t, err, "Error while reading pulumi config `poll_interval`")
+		assert.ErrorContains(t, err, "Error while reading pulumi config `starts_at`")
+
+		var validationErr *ValidationFailedError
+		assert.False(t, errors.As(err, &validationErr), "Missing fields are only reported once")
+	})
+}
+
+func TestGetConfigTime(t *testing.T) {
+	start := time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		config  map[string]string
+		want    *TestTime
+		wantErr string
+	}{
+		{
+			name: "RFC3339 timestamps",
+			config: map[string]string{
+				"project:window_start": `"2024-03-01T22:00:00Z"`,
+				"project:window_end":   `"2024-03-02T02:00:00Z"`,
+			},
+			want: &TestTime{WindowStart: start, WindowEnd: &end},
+		},
+		{
+			name: "custom layout",
+			config: map[string]string{
+				"project:release_date": `"2024-03-01"`,
+			},
+			want: &TestTime{ReleaseDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "malformed timestamp",
+			config: map[string]string{
+				"project:window_start": `"tomorrow"`,
+			},
+			wantErr: "pulumi config `window_start`: invalid time: `tomorrow` does not match the layout `2006-01-02T15:04:05Z07:00`",
+		},
+		{
+			name: "timestamp not matching the custom layout",
+			config: map[string]string{
+				"project:release_date": `"2024-03-01T22:00:00Z"`,
+			},
+			wantErr: "pulumi config `release_date`: invalid time: `2024-03-01T22:00:00Z` does not match the layout `2006-01-02`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runWithConfig(t, tt.config, func(ctx *pulumi.Context) {
+				obj := &TestTime{}
+				err := GetConfig(ctx, obj)
+				if tt.wantErr != "" {
+					assert.ErrorContains(t, err, tt.wantErr)
+					assert.ErrorIs(t, err, ErrInvalidTime)
+				} else {
+					assert.NoError(t, err)
+					assert.Equal(t, tt.want, obj)
+				}
+			})
+		})
+	}
+}
+
+func TestGetConfigEncoded(t *testing.T) {
+	policy := `{"Version":"2012-10-17","Statement":[]}`
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write([]byte(policy))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	encoded := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	runWithConfig(t, map[string]string{
+		"project:policy":   strconv.Quote(encoded),
+		"project:template": strconv.Quote(encoded),
+	}, func(ctx *pulumi.Context) {
+		obj := &TestEncoded{}
+		assert.NoError(t, GetConfig(ctx, obj))
+		assert.Equal(t, &TestEncoded{Policy: policy, Template: []byte(policy)}, obj)
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:policy": `"not base64!"`,
+	}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &TestEncoded{})
+		assert.ErrorIs(t, err, ErrInvalidEncoding)
+		assert.ErrorContains(t, err, "pulumi config `policy`")
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:policy": strconv.Quote(base64.StdEncoding.EncodeToString([]byte(policy))),
+	}, func(ctx *pulumi.Context) {
+		assert.ErrorIs(t, GetConfig(ctx, &TestEncoded{}), ErrInvalidEncoding, "Values that aren't compressed are rejected")
+	})
+}
+
+func TestRegisterDefaultImpl(t *testing.T) {
+	restoreFieldDecoders(t)
+	RegisterDefaultImpl(reflect.TypeOf((*Backend)(nil)).Elem(), func() interface{} {
+		return &TestS3Backend{}
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:backend": `{"bucket":"state"}`,
+	}, func(ctx *pulumi.Context) {
+		obj := &TestBackend{}
+		assert.NoError(t, GetConfig(ctx, obj))
+		assert.Equal(t, &TestS3Backend{Bucket: "state"}, obj.Backend)
+		assert.Equal(t, "s3://state", obj.Backend.URL())
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:backend": `{}`,
+	}, func(ctx *pulumi.Context) {
+		assert.Error(t, GetConfig(ctx, &TestBackend{}))
+	})
+}
+
+func TestGetConfigCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]string
+		want    *TestCSV
+		wantErr string
+	}{
+		{
+			name: "comma separated strings",
+			config: map[string]string{
+				"project:regions": `"us-east-1, us-west-1"`,
+				"project:ports":   `"80,443"`,
+				"project:weights": `"0.5,1.5"`,
+			},
+			want: &TestCSV{
+				Regions: []string{"us-east-1", "us-west-1"},
+				Ports:   []int{80, 443},
+				Weights: []float64{0.5, 1.5},
+			},
+		},
+		{
+			name: "JSON arrays",
+			config: map[string]string{
+				"project:regions": `["us-east-1"]`,
+				"project:ports":   `[8080]`,
+			},
+			want: &TestCSV{
+				Regions: []string{"us-east-1"},
+				Ports:   []int{8080},
+			},
+		},
+		{
+			name: "empty strings",
+			config: map[string]string{
+				"project:regions": `""`,
+				"project:ports":   `" "`,
+			},
+			want: &TestCSV{
+				Regions: []string{},
+				Ports:   []int{},
+			},
+		},
+		{
+			name: "invalid element",
+			config: map[string]string{
+				"project:ports": `"80,https"`,
+			},
+			wantErr: "pulumi config `ports`: element 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runWithConfig(t, tt.config, func(ctx *pulumi.Context) {
+				obj := &TestCSV{}
+				err := GetConfig(ctx, obj)
+				if tt.wantErr != "" {
+					assert.ErrorContains(t, err, tt.wantErr)
+				} else {
+					assert.NoError(t, err)
+					assert.Equal(t, tt.want, obj)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package pulumiconfig
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// DescribeConfig renders a human-readable table documenting each config key of the provided
+// struct, derived from its `validate` and `cfg` struct tags. Nested struct fields are listed with
+// dotted keys and the environment variables of `cfg` tags are listed in the ENV column.
+func DescribeConfig(obj interface{}) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tNAMESPACE\tREQUIRED\tDEFAULT\tENV\tALLOWED")
+	describeFields(w, reflect.TypeOf(obj), "", "", map[reflect.Type]bool{})
+	w.Flush() //nolint:errcheck // writing to a strings.Builder never fails
+	return b.String()
+}
+
+// describeFields writes a row for every config field of the struct type t and recurses into
+// nested structs. Nested fields inherit the namespace of their top-level field. Struct types that
+// are already being described further up, such as the type of a self-referential field, are
+// listed but not expanded again.
+func describeFields(w io.Writer, t reflect.Type, prefix, namespace string, visiting map[reflect.Type]bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || visiting[t] {
+		return
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		keys := getConfigKeys(field)
+		if len(keys) == 0 {
+			continue
+		}
+
+		key := prefix + keys[0]
+		fieldNamespace := namespace
+		if prefix == "" {
+			fieldNamespace = field.Tag.Get("pulumiConfigNamespace")
+			if fieldNamespace == "" {
+				fieldNamespace = "(project)"
+			}
+		}
+
+		required, defaultValue, allowed := describeValidateTag(field.Tag.Get("validate"))
+		var env string
+		if tag, err := parseCfgTag(field.Tag.Get("cfg")); err == nil {
+			required = required || tag.required
+			if tag.defaultValue != "" {
+				defaultValue = tag.defaultValue
+			}
+			// Frozen fields never read their environment variables.
+			if field.Tag.Get("frozen") != "true" {
+				env = strings.ReplaceAll(tag.env, "|", ", ")
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%t\t%s\t%s\t%s\n", key, fieldNamespace, required, defaultValue, env, allowed)
+
+		describeFields(w, field.Type, key+".", fieldNamespace, visiting)
+	}
+}
+
+// describeValidateTag extracts the required flag, the default value and the `oneof` allowed values
+// from a `validate` tag.
+func describeValidateTag(tag string) (required bool, defaultValue, allowed string) {
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required", "required_nonempty":
+			required = true
+		case "default":
+			defaultValue = param
+		case "oneof":
+			allowed = strings.Join(strings.Fields(param), ", ")
+		}
+	}
+	return required, defaultValue, allowed
+}
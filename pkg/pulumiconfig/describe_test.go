@@ -0,0 +1,50 @@
+package pulumiconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribeConfig(t *testing.T) {
+	description := DescribeConfig(&TestPulumiConfig{})
+
+	var region string
+	for _, line := range strings.Split(description, "\n") {
+		if strings.HasPrefix(line, "digital_ocean.region ") {
+			region = line
+		}
+	}
+	assert.Contains(t, region, "us-east-1, us-west-1, eu-west-1")
+	assert.Contains(t, region, "true")
+	assert.Contains(t, description, "provider_credentials.grafana_cloud.enabled")
+	assert.Contains(t, DescribeConfig(TestDefaultValue{}), "DefaultValue")
+}
+
+func TestDescribeConfigCfgTag(t *testing.T) {
+	rows := map[string][]string{}
+	for _, line := range strings.Split(DescribeConfig(TestCfgTag{}), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			rows[fields[0]] = fields
+		}
+	}
+
+	assert.Equal(t, []string{"KEY", "NAMESPACE", "REQUIRED", "DEFAULT", "ENV", "ALLOWED"}, rows["KEY"])
+	assert.Equal(t, []string{"org_id", "(project)", "true", "7", "TEST_ORG_ID"}, rows["org_id"])
+	assert.Equal(t, []string{"region", "(project)", "false", "TEST_REGION"}, rows["region"])
+	assert.Equal(t, []string{"name", "(project)", "true"}, rows["name"])
+	assert.Contains(t, DescribeConfig(TestCfgTagEnvFallback{}), "TEST_NEW_TOKEN, TEST_OLD_TOKEN")
+	assert.NotContains(t, DescribeConfig(TestCfgTagFrozen{}), "TEST_REGION", "Frozen fields don't read the environment")
+}
+
+func TestDescribeConfigRecursiveType(t *testing.T) {
+	type node struct {
+		Name string `json:"name"`
+		Next *node  `json:"next"`
+	}
+
+	description := DescribeConfig(node{})
+	assert.Contains(t, description, "next ")
+	assert.NotContains(t, description, "next.name", "A recursive type should only be expanded once")
+}
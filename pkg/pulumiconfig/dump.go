@@ -0,0 +1,81 @@
+package pulumiconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// dumpRedacted is written in place of secret values by DumpEffectiveConfig.
+const dumpRedacted = "***"
+
+// DumpEffectiveConfig renders the populated config obj as indented JSON for debugging, e.g. in CI
+// logs. Fields are named after their config keys and sorted, so the output is stable. Secret fields,
+// those of the Secret type or tagged `secret:"true"`, are replaced by `***`.
+func DumpEffectiveConfig(obj interface{}) (string, error) {
+	data, err := json.MarshalIndent(dumpValue(reflect.ValueOf(obj)), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// dumpValue converts v into plain maps, slices and values that marshal to JSON, redacting secrets.
+func dumpValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Type() == reflect.TypeOf(Secret("")) {
+		return dumpRedacted
+	}
+	if _, ok := v.Interface().(json.Marshaler); ok {
+		return v.Interface()
+	}
+
+	switch v.Kind() { //nolint:exhaustive // other kinds marshal as they are
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return dumpValue(v.Elem())
+	case reflect.Struct:
+		fields := map[string]interface{}{}
+		for i := 0; i < v.NumField(); i++ {
+			fieldType := v.Type().Field(i)
+			if !fieldType.IsExported() {
+				continue
+			}
+			name := configKeyName(fieldType)
+			if name == "" {
+				name = fieldType.Name
+			}
+			if fieldType.Tag.Get("secret") == "true" {
+				fields[name] = dumpRedacted
+				continue
+			}
+			fields[name] = dumpValue(v.Field(i))
+		}
+		return fields
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		entries := map[string]interface{}{}
+		iter := v.MapRange()
+		for iter.Next() {
+			entries[fmt.Sprint(iter.Key().Interface())] = dumpValue(iter.Value())
+		}
+		return entries
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		elements := make([]interface{}, v.Len())
+		for i := range elements {
+			elements[i] = dumpValue(v.Index(i))
+		}
+		return elements
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,56 @@
+package pulumiconfig
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDumpEffectiveConfig(t *testing.T) {
+	type database struct {
+		Host     string `json:"host" validate:"default=localhost"`
+		Password string `json:"password" secret:"true"`
+	}
+	type config struct {
+		Name      string              `json:"name"`
+		Token     Secret              `json:"token"`
+		Databases map[string]database `json:"databases" validate:"dive"`
+		Regions   []string            `json:"regions"`
+		Comment   *string             `json:"comment"`
+	}
+
+	runWithConfig(t, map[string]string{
+		"project:name":      `"DeploymentName"`,
+		"project:token":     `"s3cr3t"`,
+		"project:databases": `{"primary":{"password":"p4ssw0rd"}}`,
+		"project:regions":   `["eu-west-1","us-east-1"]`,
+	}, func(ctx *pulumi.Context) {
+		obj := &config{}
+		assert.NoError(t, GetConfig(ctx, obj))
+
+		dump, err := DumpEffectiveConfig(obj)
+		assert.NoError(t, err)
+		assert.Equal(t, `{
+  "comment": null,
+  "databases": {
+    "primary": {
+      "host": "localhost",
+      "password": "***"
+    }
+  },
+  "name": "DeploymentName",
+  "regions": [
+    "eu-west-1",
+    "us-east-1"
+  ],
+  "token": "***"
+}`, dump)
+		assert.NotContains(t, dump, "s3cr3t")
+		assert.NotContains(t, dump, "p4ssw0rd")
+
+		again, err := DumpEffectiveConfig(obj)
+		assert.NoError(t, err)
+		assert.Equal(t, dump, again, "The output should be stable")
+	})
+}
@@ -0,0 +1,119 @@
+package pulumiconfig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// MissingRequiredFieldError is returned by GetConfig when a required config key is not set, so
+// callers can tell a missing config apart from a failed validation with errors.As.
+type MissingRequiredFieldError struct {
+	Field string // The config key that is missing.
+	Err   error  // The error returned by the Pulumi config.
+}
+
+// Error returns the missing config key together with the error of the Pulumi config.
+func (e *MissingRequiredFieldError) Error() string {
+	return fmt.Sprintf("Error while reading pulumi config `%s`: %s", e.Field, e.Err)
+}
+
+// Unwrap returns the error returned by the Pulumi config.
+func (e *MissingRequiredFieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationFailedError is returned by GetConfig when the populated config fails validation.
+type ValidationFailedError struct {
+	Errors validator.ValidationErrors // The validation errors of all failing fields.
+	err    error                      // The validation errors with the config paths of the fields.
+}
+
+// Error returns the validation errors of all failing fields.
+func (e *ValidationFailedError) Error() string {
+	return fmt.Sprintf("Validation error: %s", e.err)
+}
+
+// Unwrap returns the validation errors with the config paths of the fields.
+func (e *ValidationFailedError) Unwrap() error {
+	return e.err
+}
+
+// As makes the validation errors of all failing fields available as validator.ValidationErrors,
+// so callers matching on the validator's error type keep working.
+func (e *ValidationFailedError) As(target interface{}) bool {
+	validationErrs, ok := target.(*validator.ValidationErrors)
+	if !ok || len(e.Errors) == 0 {
+		return false
+	}
+	*validationErrs = e.Errors
+	return true
+}
+
+// newValidationFailedError wraps the validation errors of validateStack, collecting the
+// validator.FieldError of every failing field.
+func newValidationFailedError(err error) *ValidationFailedError {
+	return &ValidationFailedError{Errors: collectFieldErrors(err), err: err}
+}
+
+// collectFieldErrors returns the validator.FieldError of every field validation error in the tree of err.
+func collectFieldErrors(err error) validator.ValidationErrors {
+	switch e := err.(type) { //nolint:errorlint // the error tree is walked explicitly
+	case *fieldValidationError:
+		return validator.ValidationErrors{e.err}
+	case validator.ValidationErrors:
+		return e
+	case interface{ Unwrap() []error }:
+		var fieldErrs validator.ValidationErrors
+		for _, inner := range e.Unwrap() {
+			fieldErrs = append(fieldErrs, collectFieldErrors(inner)...)
+		}
+		return fieldErrs
+	default:
+		return nil
+	}
+}
+
+// withoutRequiredErrors removes the `required` validation errors of the given top-level fields of the
+// struct type root, referenced by their Go field names, from the validation errors in err. It
+// returns nil if no validation error is left.
+func withoutRequiredErrors(err error, root reflect.Type, fields map[string]bool) error {
+	var validationErr *ValidationFailedError
+	if len(fields) == 0 || !errors.As(err, &validationErr) {
+		return err
+	}
+
+	var kept []error
+	for _, leaf := range leafErrors(validationErr.err) {
+		var fieldErr *fieldValidationError
+		if errors.As(leaf, &fieldErr) && fieldErr.err.Tag() == "required" {
+			if fields[strings.TrimPrefix(fieldErr.err.StructNamespace(), rootNamespacePrefix(root))] {
+				continue
+			}
+		}
+		kept = append(kept, leaf)
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return newValidationFailedError(errors.Join(kept...))
+}
+
+// leafErrors flattens the joined errors in the tree of err into a list.
+func leafErrors(err error) []error {
+	joined, ok := err.(interface{ Unwrap() []error }) //nolint:errorlint // the error tree is walked explicitly
+	if !ok {
+		if err == nil {
+			return nil
+		}
+		return []error{err}
+	}
+	var leaves []error
+	for _, inner := range joined.Unwrap() {
+		leaves = append(leaves, leafErrors(inner)...)
+	}
+	return leaves
+}
@@ -0,0 +1,78 @@
+package pulumiconfig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetConfigTypedErrors(t *testing.T) {
+	runWithConfig(t, map[string]string{
+		"provider:provider_credentials": `{"token":"token123"}`,
+	}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &TestPulumiConfig{})
+
+		var missingErr *MissingRequiredFieldError
+		assert.ErrorAs(t, err, &missingErr)
+		assert.Equal(t, "digital_ocean", missingErr.Field)
+		assert.ErrorContains(t, err, "Error while reading pulumi config `digital_ocean`")
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:digital_ocean":         `{"region":"invalid"}`,
+		"provider:provider_credentials": `{"token":"token123"}`,
+	}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &TestPulumiConfig{})
+
+		var validationErr *ValidationFailedError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Len(t, validationErr.Errors, 1)
+		assert.Equal(t, "oneof", validationErr.Errors[0].Tag())
+		assert.ErrorContains(t, err, "Validation error: pulumi config `digital_ocean.region` failed on the `oneof` tag")
+
+		var missingErr *MissingRequiredFieldError
+		assert.False(t, errors.As(err, &missingErr))
+	})
+
+	runWithConfig(t, map[string]string{}, func(ctx *pulumi.Context) {
+		var missingErr *MissingRequiredFieldError
+		assert.ErrorAs(t, GetConfig(ctx, &TestCfgTag{}), &missingErr)
+		assert.Equal(t, "name", missingErr.Field)
+	})
+}
+
+func TestGetConfigMissingRequiredField(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  interface{}
+	}{
+		{
+			name: "required with other rules",
+			obj: &struct {
+				Name string `json:"name" validate:"required,min=3"`
+			}{},
+		},
+		{
+			name: "required pointer",
+			obj: &struct {
+				Name *string `json:"name" validate:"required"`
+			}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runWithConfig(t, map[string]string{}, func(ctx *pulumi.Context) {
+				err := GetConfig(ctx, tt.obj)
+
+				var missingErr *MissingRequiredFieldError
+				assert.ErrorAs(t, err, &missingErr)
+				assert.Equal(t, "name", missingErr.Field)
+
+				var validationErr *ValidationFailedError
+				assert.False(t, errors.As(err, &validationErr), "The missing field should only be reported once")
+			})
+		})
+	}
+}
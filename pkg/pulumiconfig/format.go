@@ -0,0 +1,43 @@
+package pulumiconfig
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatValidationError renders an error returned by GetConfig as human-readable text, one line per
+// failing field, naming the config key of the field, e.g. `digital_ocean.region must be one of:
+// us-east-1, us-west-1, eu-west-1`. Errors that are not validation errors keep their message.
+func FormatValidationError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	entries := configErrorEntries(err)
+	lines := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		lines = append(lines, formatConfigErrorEntry(entry))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatConfigErrorEntry returns a human-readable message for a single config error.
+func formatConfigErrorEntry(entry ConfigErrorEntry) string {
+	switch entry.Tag {
+	case "":
+		return entry.Message
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", entry.Field, strings.Join(strings.Fields(entry.Param), ", "))
+	case "required", "required_nonempty":
+		return fmt.Sprintf("%s is required", entry.Field)
+	case "httpurl":
+		return fmt.Sprintf("%s must be an http(s) URL", entry.Field)
+	case "hostport":
+		return fmt.Sprintf("%s must be a host:port address", entry.Field)
+	default:
+		if entry.Param != "" {
+			return fmt.Sprintf("%s failed on the `%s=%s` validation", entry.Field, entry.Tag, entry.Param)
+		}
+		return fmt.Sprintf("%s failed on the `%s` validation", entry.Field, entry.Tag)
+	}
+}
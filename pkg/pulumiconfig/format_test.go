@@ -0,0 +1,46 @@
+package pulumiconfig
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatValidationError(t *testing.T) {
+	type config struct {
+		DigitalOcean TestDigitalOcean `json:"digital_ocean"`
+		Name         string           `json:"name" validate:"required_nonempty"`
+		Size         int              `json:"size" validate:"sizeValidation"`
+		Shards       int              `json:"shards" validate:"powerof2"`
+	}
+
+	runWithConfig(t, map[string]string{
+		"project:digital_ocean": `{"region":"ap-south-1"}`,
+		"project:size":          `5`,
+		"project:shards":        `3`,
+	}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &config{}, FieldValidation{
+			Tag:      "sizeValidation",
+			Validate: sizeValidation,
+		})
+		assert.Equal(t, "digital_ocean.region must be one of: us-east-1, us-west-1, eu-west-1\n"+
+			"name is required\n"+
+			"size failed on the `sizeValidation` validation\n"+
+			"shards failed on the `powerof2` validation", FormatValidationError(err))
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:digital_ocean": `{}`,
+		"project:name":          `"DeploymentName"`,
+		"project:shards":        `2`,
+	}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &config{}, FieldValidation{
+			Tag:      "sizeValidation",
+			Validate: sizeValidation,
+		})
+		assert.Contains(t, FormatValidationError(err), "digital_ocean.region is required")
+	})
+
+	assert.Empty(t, FormatValidationError(nil))
+}
@@ -0,0 +1,75 @@
+package pulumiconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ConfigErrorEntry is the machine-readable representation of a single config error.
+type ConfigErrorEntry struct {
+	Field     string `json:"field,omitempty"`     // The dotted config path of the field, e.g. `digital_ocean.region`.
+	Namespace string `json:"namespace,omitempty"` // The validator namespace of the field, including the root struct.
+	Tag       string `json:"tag,omitempty"`       // The validation tag that failed.
+	Param     string `json:"param,omitempty"`     // The parameter of the validation tag, if any.
+	Message   string `json:"message"`             // The human-readable error message.
+}
+
+// MarshalConfigError renders an error returned by GetConfig as a JSON array of ConfigErrorEntry
+// objects, one per failing field, so CI pipelines can annotate the offending config keys.
+// Errors that are not validation errors, e.g. a value that could not be read, are reported with
+// their message only.
+func MarshalConfigError(err error) ([]byte, error) {
+	entries := []ConfigErrorEntry{}
+	if err != nil {
+		entries = configErrorEntries(err)
+	}
+	return json.Marshal(entries)
+}
+
+// configErrorEntries flattens err into one entry per validation failure. Joined errors are split
+// into their parts and wrapped errors are unwrapped until a validation error is found.
+func configErrorEntries(err error) []ConfigErrorEntry {
+	switch e := err.(type) { //nolint:errorlint // the error tree is walked explicitly
+	case *fieldValidationError:
+		return []ConfigErrorEntry{newConfigErrorEntry(e.path, e.err, e.Error())}
+	case validator.ValidationErrors:
+		entries := make([]ConfigErrorEntry, 0, len(e))
+		for _, fe := range e {
+			_, path, _ := strings.Cut(fe.Namespace(), ".")
+			entries = append(entries, newConfigErrorEntry(path, fe, fe.Error()))
+		}
+		return entries
+	case interface{ Unwrap() []error }:
+		var entries []ConfigErrorEntry
+		for _, inner := range e.Unwrap() {
+			entries = append(entries, configErrorEntries(inner)...)
+		}
+		return entries
+	case interface{ Unwrap() error }:
+		if inner := e.Unwrap(); inner != nil && containsValidationError(inner) {
+			return configErrorEntries(inner)
+		}
+	}
+	return []ConfigErrorEntry{{Message: err.Error()}}
+}
+
+// newConfigErrorEntry creates the entry of a failed field validation.
+func newConfigErrorEntry(path string, fe validator.FieldError, message string) ConfigErrorEntry {
+	return ConfigErrorEntry{
+		Field:     path,
+		Namespace: fe.Namespace(),
+		Tag:       fe.Tag(),
+		Param:     fe.Param(),
+		Message:   message,
+	}
+}
+
+// containsValidationError reports whether err wraps a validation error anywhere in its tree.
+func containsValidationError(err error) bool {
+	var fieldErr validator.FieldError
+	var validationErrs validator.ValidationErrors
+	return errors.As(err, &fieldErr) || errors.As(err, &validationErrs)
+}
@@ -0,0 +1,70 @@
+package pulumiconfig
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalConfigError(t *testing.T) {
+	type config struct {
+		Region   string `json:"region" validate:"oneof=eu-west-1 us-east-1"`
+		Replicas int    `json:"replicas" validate:"min=1"`
+		Name     string `json:"name"`
+	}
+
+	runWithConfig(t, map[string]string{
+		"project:region":   `"invalid"`,
+		"project:replicas": `0`,
+	}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &config{})
+		assert.Error(t, err)
+
+		data, err := MarshalConfigError(err)
+		assert.NoError(t, err)
+
+		var entries []ConfigErrorEntry
+		assert.NoError(t, json.Unmarshal(data, &entries))
+		assert.Equal(t, []ConfigErrorEntry{
+			{
+				Field:     "region",
+				Namespace: "config.region",
+				Tag:       "oneof",
+				Param:     "eu-west-1 us-east-1",
+				Message:   "pulumi config `region` failed on the `oneof` tag",
+			},
+			{
+				Field:     "replicas",
+				Namespace: "config.replicas",
+				Tag:       "min",
+				Param:     "1",
+				Message:   "pulumi config `replicas` failed on the `min` tag",
+			},
+		}, entries)
+	})
+}
+
+func TestMarshalConfigErrorReadError(t *testing.T) {
+	type config struct {
+		Replicas int `json:"replicas"`
+	}
+
+	runWithConfig(t, map[string]string{
+		"project:replicas": `"abc"`,
+	}, func(ctx *pulumi.Context) {
+		data, err := MarshalConfigError(GetConfig(ctx, &config{}))
+		assert.NoError(t, err)
+
+		var entries []map[string]string
+		assert.NoError(t, json.Unmarshal(data, &entries))
+		assert.Len(t, entries, 1)
+		assert.Contains(t, entries[0]["message"], "Error while reading pulumi config `replicas`")
+		assert.NotContains(t, entries[0], "tag")
+	})
+
+	data, err := MarshalConfigError(nil)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(data))
+}
@@ -0,0 +1,40 @@
+package pulumiconfig
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// cachedConfig holds the config shared by all GetConfigOnce calls for a single type.
+type cachedConfig struct {
+	mu    sync.Mutex
+	value interface{}
+}
+
+//nolint:gochecknoglobals // cache shared by all GetConfigOnce calls
+var cachedConfigs sync.Map
+
+// GetConfigOnce populates a config of type T with GetConfig on the first call for that type and
+// returns the same shared value on every subsequent call, including concurrent ones.
+// Only successful results are cached, so a failed read is retried by the next call. There is no
+// cache invalidation: the config is read once for the lifetime of the program, and the context and
+// validators of later calls are ignored.
+func GetConfigOnce[T any](ctx *pulumi.Context, validators ...Validator) (*T, error) {
+	entry, _ := cachedConfigs.LoadOrStore(reflect.TypeOf((*T)(nil)).Elem(), &cachedConfig{})
+	cached := entry.(*cachedConfig)
+
+	cached.mu.Lock()
+	defer cached.mu.Unlock()
+	if cached.value != nil {
+		return cached.value.(*T), nil
+	}
+
+	obj, err := GetConfigAs[T](ctx, validators...)
+	if err != nil {
+		return nil, err
+	}
+	cached.value = obj
+	return obj, nil
+}
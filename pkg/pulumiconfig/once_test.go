@@ -0,0 +1,51 @@
+package pulumiconfig
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestOnce struct {
+	Name string `json:"name" validate:"counted"`
+}
+
+func TestGetConfigOnce(t *testing.T) {
+	var populations atomic.Int32
+	counted := FieldValidation{
+		Tag: "counted",
+		Validate: func(_ validator.FieldLevel) bool {
+			populations.Add(1)
+			return true
+		},
+	}
+
+	runWithConfig(t, map[string]string{
+		"project:name": `"DeploymentName"`,
+	}, func(ctx *pulumi.Context) {
+		const callers = 10
+		results := make([]*TestOnce, callers)
+
+		var wg sync.WaitGroup
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				obj, err := GetConfigOnce[TestOnce](ctx, counted)
+				assert.NoError(t, err)
+				results[i] = obj
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), populations.Load())
+		for _, obj := range results {
+			assert.Same(t, results[0], obj)
+		}
+		assert.Equal(t, "DeploymentName", results[0].Name)
+	})
+}
@@ -0,0 +1,134 @@
+package pulumiconfig
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Option configures the behavior of GetConfig. Options are passed alongside validators.
+type Option func(*options)
+
+// options holds the settings applied by the provided Option values.
+type options struct {
+	warnings          *[]Warning                 // Collects warnings emitted while reading the config.
+	rawCapture        *map[string]string         // Records the raw value of every config key read.
+	captureSecrets    bool                       // Whether secret values are recorded instead of redacted.
+	strictKeys        bool                       // Whether config keys without a matching field are reported.
+	envPrefix         string                     // The prefix of the environment variables of `cfg` tags.
+	envPrefixFallback bool                       // Whether unprefixed environment variables are used when the prefixed ones are absent.
+	ctx               context.Context            // The context of config source lookups.
+	configFile        string                     // The path of the config file of WithConfigFile.
+	fileConfig        map[string]json.RawMessage // The values of the config file, by config key.
+}
+
+// Warning describes a non-fatal problem found while reading the config.
+type Warning struct {
+	Path    string // The config key the warning applies to.
+	Message string // The human-readable warning message.
+}
+
+// Register implements the Validator interface so options can be passed to GetConfig.
+// Options don't register anything with the validator instance.
+func (o Option) Register(_ *validator.Validate) error {
+	return nil
+}
+
+// WithWarnings collects warnings emitted by GetConfig into the provided slice, in addition to logging them.
+func WithWarnings(warnings *[]Warning) Option {
+	return func(o *options) {
+		o.warnings = warnings
+	}
+}
+
+// WithRawCapture records the raw JSON value of every config key read by GetConfig into the provided
+// map, keyed by `namespace:key`, whether or not the field could be populated. Secret values are
+// redacted unless WithRawCaptureSecrets is passed as well.
+func WithRawCapture(capture *map[string]string) Option {
+	return func(o *options) {
+		o.rawCapture = capture
+	}
+}
+
+// WithRawCaptureSecrets records secret values in the raw capture of WithRawCapture instead of redacting them.
+func WithRawCaptureSecrets() Option {
+	return func(o *options) {
+		o.captureSecrets = true
+	}
+}
+
+// WithEnvPrefix prepends the prefix to the environment variables of all `cfg` tags, e.g. with the
+// prefix `TENANTA_` the tag `cfg:"key=token,env=TOKEN"` reads `TENANTA_TOKEN`.
+func WithEnvPrefix(prefix string) Option {
+	return func(o *options) {
+		o.envPrefix = prefix
+	}
+}
+
+// WithUnprefixedEnvFallback reads the unprefixed environment variable when the variable with the
+// prefix of WithEnvPrefix is absent.
+func WithUnprefixedEnvFallback() Option {
+	return func(o *options) {
+		o.envPrefixFallback = true
+	}
+}
+
+// WithContext sets the context of lookups in config sources, so a deadline or cancellation stops
+// GetConfig from waiting on a slow backend. By default the context of the Pulumi program is used.
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// getOptions applies all options found in the provided validators.
+func getOptions(validators []Validator) *options {
+	opts := &options{}
+	for _, v := range validators {
+		if o, ok := v.(Option); ok {
+			o(opts)
+		}
+	}
+	return opts
+}
+
+// warn logs the warning and records it if warnings are being collected.
+func (o *options) warn(ctx *pulumi.Context, path, message string) {
+	ctx.Log.Warn(message, nil) //nolint:errcheck // redundant error check
+	if o.warnings != nil {
+		*o.warnings = append(*o.warnings, Warning{Path: path, Message: message})
+	}
+}
+
+// capture records the raw value of the config key of the field if the raw config is being captured.
+// Values that are secret in the config, or read into a secret field, are redacted unless opted in.
+func (o *options) capture(ctx *pulumi.Context, fieldType reflect.StructField, key string) {
+	if o.rawCapture == nil {
+		return
+	}
+
+	fullKey := configFullKey(ctx, fieldType, key)
+	raw, ok := ctx.GetConfig(fullKey)
+	if !ok {
+		return
+	}
+
+	if isSecretConfig(ctx, fieldType, fullKey) && !o.captureSecrets {
+		raw = redactedSecret
+	}
+	if *o.rawCapture == nil {
+		*o.rawCapture = map[string]string{}
+	}
+	(*o.rawCapture)[fullKey] = raw
+}
+
+// context returns the context of WithContext or, if none was given, the context of the Pulumi program.
+func (o *options) context(ctx *pulumi.Context) context.Context {
+	if o.ctx != nil {
+		return o.ctx
+	}
+	return ctx.Context()
+}
@@ -0,0 +1,67 @@
+package pulumiconfig
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithWarnings(t *testing.T) {
+	runWithConfig(t, map[string]string{
+		"project:legacy_region": `"us-east-1"`,
+	}, func(ctx *pulumi.Context) {
+		var warnings []Warning
+		obj := &TestConfigKeys{}
+		assert.NoError(t, GetConfig(ctx, obj, WithWarnings(&warnings)))
+		assert.Equal(t, "us-east-1", obj.Region)
+		assert.Equal(t, []Warning{
+			{
+				Path:    "region",
+				Message: "pulumi config `legacy_region` is deprecated, use `region` instead",
+			},
+		}, warnings)
+	})
+}
+
+func TestWithRawCapture(t *testing.T) {
+	cfg := map[string]string{
+		"project:digital_ocean":         `{"region":"us-east-1"}`,
+		"provider:provider_credentials": `{"token":"token123"}`,
+		"project:org_id":                `"abc"`,
+		"project:name":                  `"DeploymentName"`,
+	}
+	runWithConfig(t, cfg, func(ctx *pulumi.Context) {
+		var capture map[string]string
+		err := GetConfig(ctx, &TestPulumiConfig{}, WithRawCapture(&capture))
+		assert.ErrorContains(t, err, "pulumi config `org_id`")
+		// Every key that was read is captured, including the one that couldn't be populated.
+		assert.Equal(t, cfg, capture)
+	})
+}
+
+func TestWithRawCaptureSecrets(t *testing.T) {
+	type config struct {
+		Token  Secret `json:"token"`
+		APIKey string `json:"api_key" secret:"true"`
+	}
+
+	runWithConfig(t, map[string]string{
+		"project:token":   `"token123"`,
+		"project:api_key": `"key123"`,
+	}, func(ctx *pulumi.Context) {
+		var capture map[string]string
+		assert.NoError(t, GetConfig(ctx, &config{}, WithRawCapture(&capture)))
+		assert.Equal(t, map[string]string{
+			"project:token":   "[secret]",
+			"project:api_key": "[secret]",
+		}, capture)
+
+		capture = nil
+		assert.NoError(t, GetConfig(ctx, &config{}, WithRawCapture(&capture), WithRawCaptureSecrets()))
+		assert.Equal(t, map[string]string{
+			"project:token":   `"token123"`,
+			"project:api_key": `"key123"`,
+		}, capture)
+	})
+}
@@ -0,0 +1,51 @@
+package pulumiconfig
+
+import (
+	"reflect"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// AsStringOutput wraps a config value in a pulumi.StringOutput so it can be passed to resource inputs.
+func AsStringOutput(ctx *pulumi.Context, value string) pulumi.StringOutput {
+	return pulumi.String(value).ToStringOutputWithContext(ctx.Context())
+}
+
+// GetConfigOutputs returns a struct with the same exported fields as the populated config obj, each
+// wrapped in the matching Pulumi output type, e.g. a `string` field becomes a pulumi.StringOutput and
+// a `map[string]int` field a pulumi.IntMapOutput. Values of types without a specific output type are
+// wrapped in a pulumi.AnyOutput. Outputs of secret config values and secret fields are marked secret.
+func GetConfigOutputs(ctx *pulumi.Context, obj interface{}) interface{} {
+	v := reflect.ValueOf(obj)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	var fields []reflect.StructField
+	var outputs []pulumi.Output
+	for i := 0; i < v.NumField(); i++ {
+		fieldType := v.Type().Field(i)
+		if !fieldType.IsExported() {
+			continue
+		}
+
+		value := v.Field(i).Interface()
+		if secret, ok := value.(Secret); ok {
+			value = secret.Value()
+		}
+		output := pulumi.ToOutputWithContext(ctx.Context(), value)
+
+		if isSecretConfig(ctx, fieldType, configFullKey(ctx, fieldType, configKeyName(fieldType))) {
+			output = pulumi.ToSecretWithContext(ctx.Context(), output)
+		}
+
+		fields = append(fields, reflect.StructField{Name: fieldType.Name, Type: reflect.TypeOf(output)})
+		outputs = append(outputs, output)
+	}
+
+	result := reflect.New(reflect.StructOf(fields)).Elem()
+	for i, output := range outputs {
+		result.Field(i).Set(reflect.ValueOf(output))
+	}
+	return result.Interface()
+}
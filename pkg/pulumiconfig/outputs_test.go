@@ -0,0 +1,69 @@
+package pulumiconfig
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/internals"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsStringOutput(t *testing.T) {
+	runWithConfig(t, map[string]string{}, func(ctx *pulumi.Context) {
+		result, err := internals.UnsafeAwaitOutput(context.Background(), AsStringOutput(ctx, "eu-west-1"))
+		assert.NoError(t, err)
+		assert.Equal(t, "eu-west-1", result.Value)
+	})
+}
+
+func TestGetConfigOutputs(t *testing.T) {
+	type config struct {
+		Name     string         `json:"name"`
+		Replicas int            `json:"replicas"`
+		Enabled  *bool          `json:"enabled"`
+		Labels   map[string]int `json:"labels"`
+		Token    Secret         `json:"token"`
+	}
+
+	runWithConfig(t, map[string]string{
+		"project:name":     `"DeploymentName"`,
+		"project:replicas": `3`,
+		"project:enabled":  `true`,
+		"project:labels":   `{"tier":1}`,
+		"project:token":    `"s3cr3t"`,
+	}, func(ctx *pulumi.Context) {
+		obj := &config{}
+		assert.NoError(t, GetConfig(ctx, obj))
+
+		outputs, ok := GetConfigOutputs(ctx, obj).(struct {
+			Name     pulumi.StringOutput
+			Replicas pulumi.IntOutput
+			Enabled  pulumi.BoolPtrOutput
+			Labels   pulumi.IntMapOutput
+			Token    pulumi.StringOutput
+		})
+		assert.True(t, ok)
+
+		tests := []struct {
+			output pulumi.Output
+			want   interface{}
+		}{
+			{output: outputs.Name, want: "DeploymentName"},
+			{output: outputs.Replicas, want: 3},
+			{output: outputs.Enabled, want: boolPtr(true)},
+			{output: outputs.Labels, want: map[string]int{"tier": 1}},
+		}
+		for _, tt := range tests {
+			result, err := internals.UnsafeAwaitOutput(context.Background(), tt.output)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result.Value)
+			assert.False(t, result.Secret)
+		}
+
+		result, err := internals.UnsafeAwaitOutput(context.Background(), outputs.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, "s3cr3t", result.Value)
+		assert.True(t, result.Secret)
+	})
+}
@@ -1,14 +1,27 @@
 package pulumiconfig
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
 )
 
+var (
+	// ErrUnsupportedFieldType is returned when a config field has a type that cannot be populated.
+	ErrUnsupportedFieldType = errors.New("unsupported field type")
+	// ErrInvalidDuration is returned when a duration config value can't be represented as a time.Duration.
+	ErrInvalidDuration = errors.New("invalid duration")
+	// ErrInvalidTime is returned when a time config value doesn't match the layout of the field.
+	ErrInvalidTime = errors.New("invalid time")
+	// ErrInvalidEncoding is returned when an encoded config value can't be decoded.
+	ErrInvalidEncoding = errors.New("invalid encoding")
+)
+
 // Validator is an interface that wraps the Register method,
 // providing a standardized way to register different types of validations.
 type Validator interface {
@@ -17,30 +30,90 @@ type Validator interface {
 
 // FieldValidation holds the information required for field-level validation.
 type FieldValidation struct {
-	Tag      string                             // The tag name used in struct fields for validation.
-	Validate func(fl validator.FieldLevel) bool // The actual field validation function.
+	Tag            string                             // The tag name used in struct fields for validation.
+	Validate       func(fl validator.FieldLevel) bool // The actual field validation function.
+	CallEvenIfNull bool                               // Whether the function is also called for nil pointers.
 }
 
 // StructValidation holds the information required for struct-level validation.
 type StructValidation struct {
 	Struct   interface{}                    // The struct type that the validation will apply to.
 	Validate func(sl validator.StructLevel) // The actual struct validation function.
+	fields   []string                       // The Go field names used by the validation, checked on registration.
+}
+
+// StructValidations holds several struct-level validations for the same struct type.
+type StructValidations struct {
+	Struct   interface{}                      // The struct type that the validations will apply to.
+	Validate []func(sl validator.StructLevel) // The struct validation functions, run in order.
+}
+
+// StructNormalization holds a normalization that is applied to a struct before it is validated.
+// A required field that is missing from the config is accepted if the normalization fills it.
+type StructNormalization struct {
+	Struct    interface{}       // The struct type that the normalization will apply to.
+	Normalize func(interface{}) // The normalization function, receiving a pointer to the struct.
+}
+
+// AliasValidation holds validation tag aliases, mapping an alias to the tags it expands to.
+type AliasValidation map[string]string
+
+// WithAliases returns a Validator that registers the given tag aliases (e.g. "port": "min=1,max=65535"),
+// so config structs can use concise custom tags.
+func WithAliases(aliases map[string]string) Validator {
+	return AliasValidation(aliases)
 }
 
 // Register adds the field validation function to the provided validator instance.
 func (fv FieldValidation) Register(validate *validator.Validate) error {
-	return validate.RegisterValidation(fv.Tag, fv.Validate)
+	return validate.RegisterValidation(fv.Tag, fv.Validate, fv.CallEvenIfNull)
 }
 
 // Register adds the struct validation function to the provided validator instance.
+// It fails if the validation uses a field that the struct type doesn't have.
 func (sv StructValidation) Register(validate *validator.Validate) error {
+	if err := checkStructFields(sv.Struct, sv.fields); err != nil {
+		return err
+	}
 	validate.RegisterStructValidation(sv.Validate, sv.Struct)
 	return nil
 }
 
+// Register adds the struct validation functions to the provided validator instance. The validator
+// keeps a single struct validation per type, so the functions are combined into one.
+func (sv StructValidations) Register(validate *validator.Validate) error {
+	validate.RegisterStructValidation(func(sl validator.StructLevel) {
+		for _, fn := range sv.Validate {
+			fn(sl)
+		}
+	}, sv.Struct)
+	return nil
+}
+
+// Register implements the Validator interface so normalizations can be passed to GetConfig.
+// Normalizations are applied before validation and don't register anything with the validator instance.
+func (sn StructNormalization) Register(_ *validator.Validate) error {
+	return nil
+}
+
+// Register adds the aliases to the provided validator instance.
+func (av AliasValidation) Register(validate *validator.Validate) error {
+	for alias, tags := range av {
+		validate.RegisterAlias(alias, tags)
+	}
+	return nil
+}
+
 // GetConfig retrieves configuration values from the Pulumi project and populates the provided object.
-// It also runs any associated validations to ensure the configuration's integrity.
+// It also runs any associated validations to ensure the configuration's integrity. A field can replace
+// its `validate` rules for a stack with a tag named after the stack, e.g. `validateProd` for `prod`.
+// Environment variables of `cfg` tags are read and `default` values are applied before any validation
+// runs, so custom validators always see the final value of a field.
 func GetConfig(ctx *pulumi.Context, obj interface{}, validators ...Validator) error {
+	opts := getOptions(validators)
+	if err := opts.loadConfigFile(); err != nil {
+		return err
+	}
 	v := reflect.ValueOf(obj)
 
 	// Dereference if obj is a pointer to get the underlying value.
@@ -48,48 +121,355 @@ func GetConfig(ctx *pulumi.Context, obj interface{}, validators ...Validator) er
 		v = v.Elem()
 	}
 
-	// Iterate over each field in the struct and fetch its configuration.
+	// Iterate over each field in the struct and fetch its configuration, collecting all read errors
+	// so that every misconfiguration is reported at once together with the validation errors.
+	fieldErrs := make([]error, v.NumField())
 	for i := 0; i < v.NumField(); i++ {
-		fieldType := v.Type().Field(i)
-		jsonTag := fieldType.Tag.Get("json")
-		if jsonTag == "" {
-			continue
+		fieldErrs[i] = populateField(ctx, opts, v.Type().Field(i), v.Field(i))
+	}
+
+	validationErr := validateConfig(ctx, obj, validators)
+
+	// Missing required fields are only reported if no normalization or default has filled them, and
+	// then only once, without the `required` validation error of the same field.
+	var errs []error
+	missingFields := map[string]bool{}
+	for i, err := range fieldErrs {
+		var missingErr *MissingRequiredFieldError
+		if errors.As(err, &missingErr) {
+			if !v.Field(i).IsZero() {
+				continue
+			}
+			missingFields[v.Type().Field(i).Name] = true
 		}
+		errs = append(errs, err)
+	}
+	validationErr = withoutRequiredErrors(validationErr, v.Type(), missingFields)
 
-		pulumiConfigNamespace := fieldType.Tag.Get("pulumiConfigNamespace")
-		cfg := config.New(ctx, pulumiConfigNamespace)
+	if opts.strictKeys {
+		errs = append(errs, checkUnknownKeys(ctx, v.Type()))
+	}
 
-		isRequired := fieldType.Tag.Get("validate") == "required"
-		if err := getConfigValue(cfg, jsonTag, v.Field(i), isRequired); err != nil {
-			return err
+	errs = append(errs, validationErr)
+	return errors.Join(errs...)
+}
+
+// ValidateEach runs the GetConfig validation pipeline on every element of an already populated
+// slice or array of configs. All elements are validated and the errors are joined, each one
+// prefixed with the index of the element it belongs to.
+func ValidateEach(ctx *pulumi.Context, slice interface{}, validators ...Validator) error {
+	v := reflect.ValueOf(slice)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fmt.Errorf("value of type `%s`: %w", v.Type(), ErrUnsupportedType)
+	}
+
+	var errs []error
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		// Validate through a pointer when possible so defaults are applied to the element itself.
+		if elem.CanAddr() && elem.Kind() != reflect.Ptr {
+			elem = elem.Addr()
+		}
+		if err := validateConfig(ctx, elem.Interface(), validators); err != nil {
+			errs = append(errs, fmt.Errorf("element %d: %w", i, err))
 		}
 	}
+	return errors.Join(errs...)
+}
 
+// validateConfig validates obj using the provided validators together with the built-in validations.
+func validateConfig(ctx *pulumi.Context, obj interface{}, validators []Validator) error {
 	// Initialize the validator and register custom validation rules.
 	validate := validator.New()
-	validators = append(validators, GetValidations(ctx)...)
+	validate.RegisterTagNameFunc(configKeyName)
+	v := &Validation{ctx: ctx, defaultFuncs: getDefaultFuncs(validators)}
+	validators = append(validators, v.validations()...)
 	if err := registerValidations(validate, validators); err != nil {
 		return err
 	}
 
-	// Validate the struct using the initialized validator.
-	if err := validate.Struct(obj); err != nil {
-		return fmt.Errorf("Validation error: %w", err)
+	normalize(obj, validators)
+	v.applyDefaults(reflect.ValueOf(obj))
+
+	// Validate the struct using the initialized validator, applying the rules of the current stack.
+	if err := validateStack(ctx, validate, obj, validators); err != nil {
+		return newValidationFailedError(err)
+	}
+
+	return nil
+}
+
+// configKeyName returns the config key of a struct field, which is used as its name in validation errors.
+// Fields without a config key keep their Go field name.
+func configKeyName(field reflect.StructField) string {
+	if keys := getConfigKeys(field); len(keys) > 0 && keys[0] != "-" {
+		return keys[0]
+	}
+	return ""
+}
+
+// fieldValidationError is the validation error of a single field, reported with its dotted config path.
+type fieldValidationError struct {
+	path string
+	err  validator.FieldError
+}
+
+// Error returns the config path of the field together with the failed validation tag.
+func (e *fieldValidationError) Error() string {
+	return fmt.Sprintf("pulumi config `%s` failed on the `%s` tag", e.path, e.err.Tag())
+}
+
+// Unwrap returns the underlying validator.FieldError.
+func (e *fieldValidationError) Unwrap() error {
+	return e.err
+}
+
+// withConfigPaths splits validation errors into one error per failing field, each reporting the
+// dotted config path of the field, e.g. `provider_credentials.grafana_cloud.enabled`, so the error
+// pinpoints the exact config key. The validated value is used to drop the name of its type from the
+// namespaces of the errors.
+func withConfigPaths(err error, obj interface{}) error {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	prefix := rootNamespacePrefix(reflect.TypeOf(obj))
+	errs := make([]error, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		path := strings.TrimPrefix(fe.Namespace(), prefix)
+		errs = append(errs, &fieldValidationError{path: path, err: fe})
+	}
+	return errors.Join(errs...)
+}
+
+// rootNamespacePrefix returns the prefix that the validator adds to the namespaces of the fields of
+// the struct type t: the name of the type followed by a dot, or nothing for anonymous structs.
+func rootNamespacePrefix(t reflect.Type) string {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Name() == "" {
+		return ""
+	}
+	return t.Name() + "."
+}
+
+// GetConfigAs allocates a new T, populates and validates it like GetConfig and returns it.
+func GetConfigAs[T any](ctx *pulumi.Context, validators ...Validator) (*T, error) {
+	obj := new(T)
+	if err := GetConfig(ctx, obj, validators...); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// populateField fetches the configuration of a single struct field.
+func populateField(ctx *pulumi.Context, opts *options, fieldType reflect.StructField, field reflect.Value) error {
+	// Fields backed by an external config source are not read from the Pulumi config.
+	if source := fieldType.Tag.Get("source"); source != "" {
+		return getSourceValue(opts.context(ctx), source, field)
+	}
+
+	// The combined `cfg` tag supersedes the individual tags.
+	if tag, ok := fieldType.Tag.Lookup("cfg"); ok {
+		if keys := getConfigKeys(fieldType); len(keys) > 0 {
+			opts.capture(ctx, fieldType, keys[0])
+		}
+		return getCfgValue(ctx, opts, fieldType, field, tag)
+	}
+
+	keys := getConfigKeys(fieldType)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	// Only a single level of pointer indirection is supported.
+	if fieldType.Type.Kind() == reflect.Ptr && fieldType.Type.Elem().Kind() == reflect.Ptr {
+		return fmt.Errorf("field `%s` of type `%s`: %w", fieldType.Name, fieldType.Type, ErrUnsupportedFieldType)
+	}
+
+	pulumiConfigNamespace := fieldType.Tag.Get("pulumiConfigNamespace")
+	cfg := config.New(ctx, pulumiConfigNamespace)
+
+	// Use the first key that is set, warning when a legacy key is used.
+	key, index := resolveConfigKey(cfg, keys)
+	if index > 0 {
+		opts.warn(ctx, keys[0], fmt.Sprintf("pulumi config `%s` is deprecated, use `%s` instead", key, keys[0]))
+	}
+	opts.capture(ctx, fieldType, key)
+
+	return getConfigValue(ctx, opts, cfg, key, field, fieldType.Tag)
+}
+
+// ReloadConfig re-reads the configuration into an already populated object.
+// The configuration is read and validated into a fresh value first, so values that are no longer
+// present in the config are reset and defaults are applied again. On success the existing object is
+// overwritten entirely, on error it is left unchanged. The object must be a non-nil pointer.
+func ReloadConfig(ctx *pulumi.Context, obj interface{}, validators ...Validator) error {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("value of type `%T`: %w", obj, ErrUnsupportedType)
+	}
+	v = v.Elem()
+
+	fresh := reflect.New(v.Type())
+	if err := GetConfig(ctx, fresh.Interface(), validators...); err != nil {
+		return err
 	}
 
+	v.Set(fresh.Elem())
+	return nil
+}
+
+// getConfigKeys returns the ordered list of config keys for a field.
+// The key of the `cfg` tag takes precedence over the `configKey` tag, then the `configKeys` tag and
+// finally the `json` tag when present. The `configKey` tag decouples the Pulumi config key from the
+// JSON name of the field. The keys of the comma separated `configKeys` tag are trimmed and empty
+// keys are skipped.
+func getConfigKeys(field reflect.StructField) []string {
+	if tag, err := parseCfgTag(field.Tag.Get("cfg")); err == nil && tag.key != "" {
+		return []string{tag.key}
+	}
+	if configKey := field.Tag.Get("configKey"); configKey != "" {
+		return []string{configKey}
+	}
+	if configKeys := field.Tag.Get("configKeys"); configKeys != "" {
+		var keys []string
+		for _, key := range strings.Split(configKeys, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) > 0 {
+			return keys
+		}
+	}
+	if jsonTag := field.Tag.Get("json"); jsonTag != "" {
+		return []string{jsonTag}
+	}
 	return nil
 }
 
-// getConfigValue fetches the configuration value based on its type and if it's a required field.
-func getConfigValue(cfg *config.Config, jsonTag string, field reflect.Value, isRequired bool) error {
-	if field.Kind() == reflect.Ptr {
-		return cfg.GetObject(jsonTag, field.Addr().Interface())
-	} else if err := cfg.TryObject(jsonTag, field.Addr().Interface()); err != nil && isRequired {
+// resolveConfigKey returns the first key that is set in the config together with its index.
+// If none of the keys are set, the first key is returned with an index of -1.
+func resolveConfigKey(cfg *config.Config, keys []string) (string, int) {
+	for i, key := range keys {
+		if _, err := cfg.Try(key); err == nil {
+			return key, i
+		}
+	}
+	return keys[0], -1
+}
+
+// getConfigValue fetches the configuration value based on its type and the field's struct tags.
+// Values missing from the Pulumi config are read from the config file of WithConfigFile, if any,
+// unless the field is tagged `frozen:"true"`. A missing value is only an error for required fields,
+// but a value that is present and cannot be decoded into the field is always reported. Values are
+// decoded with decodeFieldValue.
+func getConfigValue(ctx *pulumi.Context, opts *options, cfg *config.Config, jsonTag string, field reflect.Value, tag reflect.StructTag) error {
+	isRequired := hasValidateRule(tag.Get("validate"), "required")
+	if isSecretOutputField(field, tag) {
+		return getSecretOutput(cfg, jsonTag, field, isRequired)
+	}
+
+	// Frozen fields only come from the Pulumi config, never from the config file.
+	raw, err := cfg.Try(jsonTag)
+	if err != nil && tag.Get("frozen") != "true" {
+		if fileRaw, ok := opts.fileValue(ctx, tag, jsonTag); ok {
+			raw, err = fileRaw, nil
+		}
+	}
+	if err != nil {
+		if isRequired {
+			return &MissingRequiredFieldError{Field: jsonTag, Err: err}
+		}
+		return nil
+	}
+
+	if err := decodeFieldValue(ctx, raw, field, tag); err != nil {
 		return fmt.Errorf("Error while reading pulumi config `%s`: %w", jsonTag, err)
 	}
 	return nil
 }
 
+// decodeFieldValue decodes a raw JSON config value into the field based on its type and struct tags,
+// for fields with and without a `cfg` tag. Values of the form `${ref:namespace:key}` are replaced
+// by the value of the referenced config key. For flexible list fields a scalar value is accepted
+// and decoded as a single-element list, and slice fields with the `configFormat:"csv"` tag also
+// accept a comma separated string. Duration fields, including pointers to durations, accept
+// strings like `1h30m` and numbers, which are interpreted in the unit of the `unit` tag or as
+// nanoseconds. Time fields, including pointers to times, are parsed with the layout of the
+// `timeLayout` tag or RFC3339. String and byte slice fields with the `encoding:"gzip+base64"` tag
+// are base64 decoded and decompressed, and pulumi.StringOutput fields tagged `secret:"true"`
+// receive the value as a secret output.
+func decodeFieldValue(ctx *pulumi.Context, raw string, field reflect.Value, tag reflect.StructTag) error {
+	raw, err := resolveReference(ctx, raw)
+	if err != nil {
+		return err
+	}
+
+	if tag.Get("flexlist") == "true" && field.Kind() == reflect.Slice {
+		raw = wrapScalar(raw)
+	}
+
+	isDurationPtr := field.Kind() == reflect.Ptr && isDurationType(field.Type().Elem())
+	isTimePtr := field.Kind() == reflect.Ptr && isTimeType(field.Type().Elem())
+
+	switch {
+	case isSecretOutputField(field, tag):
+		setSecretOutput(ctx, raw, field)
+	case tag.Get("encoding") != "":
+		err = decodeEncoded(raw, field, tag.Get("encoding"))
+	case tag.Get("configFormat") == "csv" && field.Kind() == reflect.Slice:
+		err = decodeCSV(raw, field)
+	case isDurationPtr:
+		value := reflect.New(field.Type().Elem())
+		if err = decodeDuration(raw, value.Elem(), tag.Get("unit")); err == nil {
+			field.Set(value)
+		}
+	case isDurationType(field.Type()):
+		err = decodeDuration(raw, field, tag.Get("unit"))
+	case isTimePtr:
+		value := reflect.New(field.Type().Elem())
+		if err = decodeTime(raw, value.Elem(), tag.Get("timeLayout")); err == nil {
+			field.Set(value)
+		}
+	case isTimeType(field.Type()):
+		err = decodeTime(raw, field, tag.Get("timeLayout"))
+	default:
+		err = decodeConfigValue(raw, field)
+	}
+	return err
+}
+
+// normalize applies all struct normalizations for the type of obj, in the order they were provided.
+// Only pointers can be normalized, any other value is left as is.
+func normalize(obj interface{}, validators []Validator) {
+	t := reflect.TypeOf(obj)
+	if t.Kind() != reflect.Ptr {
+		return
+	}
+	for _, v := range validators {
+		if sn, ok := v.(StructNormalization); ok && reflect.TypeOf(sn.Struct) == t.Elem() {
+			sn.Normalize(obj)
+		}
+	}
+}
+
+// hasValidateRule reports whether the `validate` tag contains the given rule.
+func hasValidateRule(tag, rule string) bool {
+	for _, r := range strings.Split(tag, ",") {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
 // registerValidations registers all provided validators to the provided validator instance.
 func registerValidations(validate *validator.Validate, validators []Validator) error {
 	for _, v := range validators {
@@ -3,11 +3,13 @@ package pulumiconfig
 import (
 	"encoding/json"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -44,6 +46,10 @@ type TestSize struct {
 	Size int `json:"size" validate:"sizeValidation=0"`
 }
 
+type TestAlias struct {
+	Port int `json:"port" validate:"port"`
+}
+
 type TestDefaultValue struct {
 	DefaultString string  `json:"default_string" validate:"default=DefaultValue"`
 	DefaultInt    int     `json:"default_int" validate:"default=100"`
@@ -51,6 +57,46 @@ type TestDefaultValue struct {
 	DefaultFloat  float32 `json:"default_float" validate:"default=24.24"`
 }
 
+type TestConfigKeys struct {
+	Region string `json:"region" configKeys:"region,legacy_region" validate:"required"`
+}
+
+type TestConfigKeysSpaced struct {
+	Region string `json:"region" configKeys:"region, legacy_region," validate:"required"`
+}
+
+type TestDoublePointer struct {
+	GrafanaCloud **TestGrafanaCloud `json:"grafana_cloud"`
+}
+
+type TestRawProviders struct {
+	Providers map[string]json.RawMessage `json:"providers"`
+}
+
+type TestLargeInt struct {
+	ID     int64  `json:"id"`
+	Serial uint64 `json:"serial"`
+	Small  int8   `json:"small"`
+}
+
+type TestServices struct {
+	Services map[string]TestService `json:"services" validate:"dive"`
+}
+
+type TestService struct {
+	Image    string `json:"image" validate:"required"`
+	Replicas int    `json:"replicas" validate:"default=1"`
+}
+
+type TestServers struct {
+	Servers []TestServer `json:"servers" validate:"unique=Name,dive"`
+}
+
+type TestServer struct {
+	Name string `json:"name" validate:"required"`
+	Size int    `json:"size"`
+}
+
 type TestGrafanaCloud struct {
 	Enabled bool `json:"enabled"`
 }
@@ -60,11 +106,40 @@ type TestProviderCredentials struct {
 	GrafanaCloud TestGrafanaCloud `json:"grafana_cloud"`
 }
 
+// runWithConfig sets the given Pulumi config and runs fn in a mocked Pulumi program.
+func runWithConfig(t *testing.T, cfg map[string]string, fn func(ctx *pulumi.Context)) {
+	t.Helper()
+	runWithStackConfig(t, "stack", cfg, fn)
+}
+
+// runWithStackConfig sets the given Pulumi config and runs fn in a mocked Pulumi program for the given stack.
+func runWithStackConfig(t *testing.T, stack string, cfg map[string]string, fn func(ctx *pulumi.Context)) {
+	t.Helper()
+	jsonConfig, err := json.Marshal(cfg)
+	assert.NoError(t, err, "Error marshaling to JSON")
+
+	err = os.Setenv(pulumi.EnvConfig, string(jsonConfig))
+	assert.NoError(t, err)
+
+	err = pulumi.RunErr(func(ctx *pulumi.Context) error {
+		fn(ctx)
+		return nil
+	},
+		pulumi.WithMocks("project", stack, mocks(0)),
+	)
+	assert.NoError(t, err)
+}
+
 // stringPtr is a utility function to convert a string into a pointer for easier comparison in tests.
 func stringPtr(s string) *string {
 	return &s
 }
 
+// boolPtr is a utility function to convert a bool into a pointer for easier comparison in tests.
+func boolPtr(b bool) *bool {
+	return &b
+}
+
 // sizeValidation is a custom validation function that ensures the field value is greater than or equal to 10.
 func sizeValidation(fl validator.FieldLevel) bool {
 	size := fl.Field().Int()
@@ -151,7 +226,9 @@ func TestGetConfig(t *testing.T) {
 			args: args{
 				obj: &TestPulumiConfig{},
 			},
-			want:    &TestPulumiConfig{},
+			want: &TestPulumiConfig{
+				GrafanaCloud: &TestGrafanaCloud{Enabled: true},
+			},
 			wantErr: true,
 		},
 		{
@@ -222,11 +299,39 @@ func TestGetConfig(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "using alias with valid value",
+			config: map[string]string{
+				"project:port": `8080`,
+			},
+			args: args{
+				obj:         &TestAlias{},
+				validations: []Validator{WithAliases(map[string]string{"port": "min=1,max=65535"})},
+			},
+			want: &TestAlias{
+				Port: 8080,
+			},
+			wantErr: false,
+		},
+		{
+			name: "using alias with invalid value",
+			config: map[string]string{
+				"project:port": `70000`,
+			},
+			args: args{
+				obj:         &TestAlias{},
+				validations: []Validator{WithAliases(map[string]string{"port": "min=1,max=65535"})},
+			},
+			want: &TestAlias{
+				Port: 70000,
+			},
+			wantErr: true,
+		},
 		{
 			name: "default value is set",
 			config: map[string]string{
 				"project:default_string": `"some_value"`,
-				"project:default_int":    `"10"`,
+				"project:default_int":    `10`,
 				"project:default_uint":   `66`,
 				"project:default_float":  `12.13`,
 			},
@@ -235,7 +340,7 @@ func TestGetConfig(t *testing.T) {
 			},
 			want: &TestDefaultValue{
 				DefaultString: "some_value",
-				DefaultInt:    100,
+				DefaultInt:    10,
 				DefaultUInt:   66,
 				DefaultFloat:  12.13,
 			},
@@ -255,6 +360,74 @@ func TestGetConfig(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "non-required field has wrong type",
+			config: map[string]string{
+				"project:digital_ocean":         `{"region":"us-east-1"}`,
+				"provider:provider_credentials": `{"token":"token123", "grafana_cloud": {"enabled":true}}`,
+				"project:org_id":                `"not-a-number"`,
+			},
+			args: args{
+				obj: &TestPulumiConfig{},
+			},
+			want: &TestPulumiConfig{
+				DigitalOcean: TestDigitalOcean{Region: "us-east-1"},
+				ProviderCredentials: &TestProviderCredentials{
+					Token:        "token123",
+					GrafanaCloud: TestGrafanaCloud{Enabled: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "config key is present",
+			config: map[string]string{
+				"project:region":        `"eu-west-1"`,
+				"project:legacy_region": `"us-east-1"`,
+			},
+			args: args{
+				obj: &TestConfigKeys{},
+			},
+			want: &TestConfigKeys{
+				Region: "eu-west-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "only legacy config key is present",
+			config: map[string]string{
+				"project:legacy_region": `"us-east-1"`,
+			},
+			args: args{
+				obj: &TestConfigKeys{},
+			},
+			want: &TestConfigKeys{
+				Region: "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "legacy config key listed with spaces",
+			config: map[string]string{
+				"project:legacy_region": `"us-east-1"`,
+			},
+			args: args{
+				obj: &TestConfigKeysSpaced{},
+			},
+			want: &TestConfigKeysSpaced{
+				Region: "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name:   "none of the config keys are present",
+			config: map[string]string{},
+			args: args{
+				obj: &TestConfigKeys{},
+			},
+			want:    &TestConfigKeys{},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -282,3 +455,533 @@ func TestGetConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_resolveConfigKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    map[string]string
+		keys      []string
+		wantKey   string
+		wantIndex int
+	}{
+		{
+			name: "first key is used",
+			config: map[string]string{
+				"project:new_key":    `"new"`,
+				"project:legacy_key": `"legacy"`,
+			},
+			keys:      []string{"new_key", "legacy_key"},
+			wantKey:   "new_key",
+			wantIndex: 0,
+		},
+		{
+			name: "legacy key is used",
+			config: map[string]string{
+				"project:legacy_key": `"legacy"`,
+			},
+			keys:      []string{"new_key", "legacy_key"},
+			wantKey:   "legacy_key",
+			wantIndex: 1,
+		},
+		{
+			name:      "no key is set",
+			config:    map[string]string{},
+			keys:      []string{"new_key", "legacy_key"},
+			wantKey:   "new_key",
+			wantIndex: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runWithConfig(t, tt.config, func(ctx *pulumi.Context) {
+				key, index := resolveConfigKey(config.New(ctx, ""), tt.keys)
+				assert.Equal(t, tt.wantKey, key)
+				assert.Equal(t, tt.wantIndex, index)
+			})
+		})
+	}
+}
+
+func TestGetConfigUnsupportedFieldType(t *testing.T) {
+	runWithConfig(t, map[string]string{
+		"project:grafana_cloud": `{"enabled":true}`,
+	}, func(ctx *pulumi.Context) {
+		obj := &TestDoublePointer{}
+		err := GetConfig(ctx, obj)
+		assert.ErrorIs(t, err, ErrUnsupportedFieldType)
+		assert.ErrorContains(t, err, "GrafanaCloud")
+		assert.ErrorContains(t, err, "**pulumiconfig.TestGrafanaCloud")
+		assert.Nil(t, obj.GrafanaCloud)
+	})
+}
+
+func TestGetConfigRawMessageMap(t *testing.T) {
+	runWithConfig(t, map[string]string{
+		"project:providers": `{"grafana_cloud":{"enabled":true},"region":"us-east-1"}`,
+	}, func(ctx *pulumi.Context) {
+		obj := &TestRawProviders{}
+		assert.NoError(t, GetConfig(ctx, obj))
+		assert.JSONEq(t, `{"enabled":true}`, string(obj.Providers["grafana_cloud"]))
+		assert.JSONEq(t, `"us-east-1"`, string(obj.Providers["region"]))
+
+		grafanaCloud := TestGrafanaCloud{}
+		assert.NoError(t, json.Unmarshal(obj.Providers["grafana_cloud"], &grafanaCloud))
+		assert.Equal(t, TestGrafanaCloud{Enabled: true}, grafanaCloud)
+	})
+}
+
+func TestGetConfigLargeInt(t *testing.T) {
+	runWithConfig(t, map[string]string{
+		"project:id":     `9007199254740993`,
+		"project:serial": `18446744073709551615`,
+	}, func(ctx *pulumi.Context) {
+		obj := &TestLargeInt{}
+		assert.NoError(t, GetConfig(ctx, obj))
+		assert.Equal(t, int64(9007199254740993), obj.ID)
+		assert.Equal(t, uint64(18446744073709551615), obj.Serial)
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:small": `300`,
+	}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &TestLargeInt{})
+		assert.ErrorContains(t, err, "pulumi config `small`")
+	})
+}
+
+func TestGetConfigMapOfStructs(t *testing.T) {
+	runWithConfig(t, map[string]string{
+		"project:services": `{"api":{"image":"api:1.0","replicas":3},"worker":{"image":"worker:1.0"}}`,
+	}, func(ctx *pulumi.Context) {
+		obj := &TestServices{}
+		assert.NoError(t, GetConfig(ctx, obj))
+		assert.Equal(t, map[string]TestService{
+			"api":    {Image: "api:1.0", Replicas: 3},
+			"worker": {Image: "worker:1.0", Replicas: 1},
+		}, obj.Services)
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:services": `{"api":{"replicas":3},"worker":{"image":"worker:1.0"},"cron":{"image":"cron:1.0"}}`,
+	}, func(ctx *pulumi.Context) {
+		obj := &TestServices{}
+		err := GetConfig(ctx, obj)
+		assert.EqualError(t, err, "Validation error: pulumi config `services[api].image` failed on the `required` tag")
+		assert.Equal(t, 1, obj.Services["worker"].Replicas, "Every entry should get its defaults")
+		assert.Equal(t, 1, obj.Services["cron"].Replicas, "Every entry should get its defaults")
+	})
+}
+
+func TestGetConfigUniqueSliceElements(t *testing.T) {
+	tests := []struct {
+		name    string
+		servers string
+		wantErr bool
+	}{
+		{
+			name:    "unique names",
+			servers: `[{"name":"web","size":1},{"name":"db","size":2}]`,
+			wantErr: false,
+		},
+		{
+			name:    "duplicate names",
+			servers: `[{"name":"web","size":1},{"name":"web","size":2}]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runWithConfig(t, map[string]string{
+				"project:servers": tt.servers,
+			}, func(ctx *pulumi.Context) {
+				obj := &TestServers{}
+				err := GetConfig(ctx, obj)
+				if tt.wantErr {
+					assert.ErrorContains(t, err, "unique")
+				} else {
+					assert.NoError(t, err)
+					assert.Len(t, obj.Servers, 2)
+				}
+			})
+		})
+	}
+}
+
+func TestGetConfigNestedValidationPath(t *testing.T) {
+	type grafanaCloud struct {
+		Stack string `json:"stack" validate:"oneof=prod-eu prod-us"`
+	}
+	type providerCredentials struct {
+		GrafanaCloud grafanaCloud `json:"grafana_cloud"`
+	}
+	type nested struct {
+		ProviderCredentials providerCredentials `json:"provider_credentials"`
+	}
+
+	runWithConfig(t, map[string]string{
+		"project:provider_credentials": `{"grafana_cloud":{"stack":"invalid"}}`,
+	}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &nested{})
+		assert.ErrorContains(t, err, "pulumi config `provider_credentials.grafana_cloud.stack` failed on the `oneof` tag")
+
+		var validationErrs validator.ValidationErrors
+		assert.ErrorAs(t, err, &validationErrs)
+		assert.Len(t, validationErrs, 1)
+		assert.Equal(t, "oneof", validationErrs[0].Tag())
+
+		// The validator doesn't prefix the namespaces of anonymous structs with a type name.
+		err = GetConfig(ctx, &struct {
+			ProviderCredentials providerCredentials `json:"provider_credentials"`
+		}{})
+		assert.ErrorContains(t, err, "pulumi config `provider_credentials.grafana_cloud.stack` failed on the `oneof` tag")
+	})
+}
+
+func TestGetConfigAggregatesErrors(t *testing.T) {
+	runWithConfig(t, map[string]string{
+		"project:digital_ocean":         `{"region":"invalid"}`,
+		"provider:provider_credentials": `{"token":"token123"}`,
+		"project:org_id":                `"abc"`,
+		"project:name":                  `"token123"`,
+	}, func(ctx *pulumi.Context) {
+		obj := &TestPulumiConfig{}
+		err := GetConfig(ctx, obj, StructValidation{
+			Struct:   TestPulumiConfig{},
+			Validate: nameNotEqualToToken,
+		})
+		assert.ErrorContains(t, err, "Error while reading pulumi config `org_id`")
+		assert.ErrorContains(t, err, "pulumi config `digital_ocean.region` failed on the `oneof` tag")
+		assert.ErrorContains(t, err, "pulumi config `Name` failed on the `name_eq_token` tag")
+		assert.Equal(t, "token123", obj.Name, "Fields after a failing one should still be populated")
+	})
+
+	type placement struct {
+		Region string `json:"region" validate:"required"`
+		Zone   string `json:"zone" validate:"oneof=a b c"`
+	}
+	runWithConfig(t, map[string]string{
+		"project:zone": `"d"`,
+	}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &placement{})
+		assert.ErrorContains(t, err, "Error while reading pulumi config `region`")
+		assert.ErrorContains(t, err, "pulumi config `zone` failed on the `oneof` tag")
+		assert.Equal(t, 1, strings.Count(err.Error(), "`region`"), "A missing required field should be reported once")
+
+		var validationErr *ValidationFailedError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Len(t, validationErr.Errors, 1)
+	})
+}
+
+func TestGetConfigRequiredIf(t *testing.T) {
+	type grafanaCloud struct {
+		APIKey string `json:"api_key" validate:"required_if=Enabled true"`
+		URL    string `json:"url" validate:"required_with=Stack"`
+		// Declared after the fields depending on it, so its default must be set before they're validated.
+		Stack   string `json:"stack" validate:"default=prod-eu"`
+		Enabled bool   `json:"enabled"`
+	}
+	type config struct {
+		GrafanaCloud grafanaCloud `json:"grafana_cloud"`
+		Enabled      bool         `cfg:"key=monitoring_enabled,env=TEST_MONITORING_ENABLED"`
+		Token        string       `json:"token" validate:"required_if=Enabled true"`
+	}
+
+	tests := []struct {
+		name    string
+		cfg     map[string]string
+		env     string
+		wantErr []string
+	}{
+		{
+			name: "enabled with api key and default stack",
+			cfg: map[string]string{
+				"project:grafana_cloud": `{"enabled":true,"api_key":"key","url":"https://grafana.net"}`,
+			},
+		},
+		{
+			name: "enabled without api key",
+			cfg: map[string]string{
+				"project:grafana_cloud": `{"enabled":true,"url":"https://grafana.net"}`,
+			},
+			wantErr: []string{"pulumi config `grafana_cloud.api_key` failed on the `required_if` tag"},
+		},
+		{
+			name: "default stack requires url",
+			cfg: map[string]string{
+				"project:grafana_cloud": `{"enabled":false}`,
+			},
+			wantErr: []string{"pulumi config `grafana_cloud.url` failed on the `required_with` tag"},
+		},
+		{
+			name: "enabled through env without token",
+			cfg: map[string]string{
+				"project:grafana_cloud": `{"url":"https://grafana.net"}`,
+			},
+			env:     "true",
+			wantErr: []string{"pulumi config `token` failed on the `required_if` tag"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_MONITORING_ENABLED", tt.env)
+			runWithConfig(t, tt.cfg, func(ctx *pulumi.Context) {
+				err := GetConfig(ctx, &config{})
+				if len(tt.wantErr) == 0 {
+					assert.NoError(t, err)
+				}
+				for _, want := range tt.wantErr {
+					assert.ErrorContains(t, err, want)
+				}
+			})
+		})
+	}
+}
+
+func TestGetConfigDefaultBeforeCustomValidator(t *testing.T) {
+	// The custom validator is listed before the default, so it relies on the defaults pre-pass.
+	type config struct {
+		Size  int `json:"size" validate:"sizeValidation,default=20"`
+		Count int `cfg:"key=count,env=TEST_COUNT" validate:"sizeValidation"`
+	}
+
+	t.Setenv("TEST_COUNT", "12")
+	runWithConfig(t, map[string]string{}, func(ctx *pulumi.Context) {
+		obj := &config{}
+		err := GetConfig(ctx, obj, FieldValidation{
+			Tag:      "sizeValidation",
+			Validate: sizeValidation,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, &config{Size: 20, Count: 12}, obj)
+	})
+}
+
+func TestGetConfigBoolPointer(t *testing.T) {
+	type config struct {
+		Enabled    *bool `json:"enabled"`
+		Monitoring *bool `cfg:"key=monitoring,env=TEST_MONITORING"`
+		Backups    *bool `json:"backups" validate:"default=true"`
+	}
+
+	tests := []struct {
+		name   string
+		config map[string]string
+		env    string
+		want   *config
+	}{
+		{
+			name:   "unset",
+			config: map[string]string{},
+			want:   &config{Backups: boolPtr(true)},
+		},
+		{
+			name: "explicitly false",
+			config: map[string]string{
+				"project:enabled": `false`,
+				"project:backups": `false`,
+			},
+			env:  "false",
+			want: &config{Enabled: boolPtr(false), Monitoring: boolPtr(false), Backups: boolPtr(false)},
+		},
+		{
+			name: "explicitly true",
+			config: map[string]string{
+				"project:enabled": `true`,
+				"project:backups": `true`,
+			},
+			env:  "true",
+			want: &config{Enabled: boolPtr(true), Monitoring: boolPtr(true), Backups: boolPtr(true)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_MONITORING", tt.env)
+			runWithConfig(t, tt.config, func(ctx *pulumi.Context) {
+				obj := &config{}
+				assert.NoError(t, GetConfig(ctx, obj))
+				assert.Equal(t, tt.want, obj)
+			})
+		})
+	}
+}
+
+func TestStructValidations(t *testing.T) {
+	nameNotEmpty := func(sl validator.StructLevel) {
+		root := sl.Current().Interface().(TestPulumiConfig)
+		if root.Name == "" {
+			sl.ReportError(root.Name, "Name", "name", "name_not_empty", "")
+		}
+	}
+
+	runWithConfig(t, map[string]string{
+		"project:digital_ocean":         `{"region":"us-east-1"}`,
+		"provider:provider_credentials": `{"token":"token123"}`,
+		"project:name":                  `"token123"`,
+	}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &TestPulumiConfig{}, StructValidations{
+			Struct:   TestPulumiConfig{},
+			Validate: []func(sl validator.StructLevel){nameNotEqualToToken, nameNotEmpty},
+		})
+		assert.ErrorContains(t, err, "pulumi config `Name` failed on the `name_eq_token` tag")
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:digital_ocean":         `{"region":"us-east-1"}`,
+		"provider:provider_credentials": `{"token":"token123"}`,
+	}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &TestPulumiConfig{}, StructValidations{
+			Struct:   TestPulumiConfig{},
+			Validate: []func(sl validator.StructLevel){nameNotEqualToToken, nameNotEmpty},
+		})
+		assert.ErrorContains(t, err, "pulumi config `Name` failed on the `name_not_empty` tag")
+		assert.NotContains(t, err.Error(), "name_eq_token")
+	})
+}
+
+func TestGetConfigDiveSliceDefaults(t *testing.T) {
+	type peer struct {
+		Name string `json:"name" validate:"required"`
+		// The custom validator runs before the default, so it relies on the defaults pre-pass.
+		Weight int `json:"weight" validate:"sizeValidation,default=10,max=100"`
+	}
+	type config struct {
+		Peers []peer `json:"peers" validate:"dive"`
+	}
+	sizeValidator := FieldValidation{
+		Tag:      "sizeValidation",
+		Validate: sizeValidation,
+	}
+
+	runWithConfig(t, map[string]string{
+		"project:peers": `[{"name":"a","weight":50},{"name":"b"}]`,
+	}, func(ctx *pulumi.Context) {
+		obj := &config{}
+		assert.NoError(t, GetConfig(ctx, obj, sizeValidator))
+		assert.Equal(t, []peer{{Name: "a", Weight: 50}, {Name: "b", Weight: 10}}, obj.Peers)
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:peers": `[{"name":"a","weight":200},{"name":"b"}]`,
+	}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &config{}, sizeValidator)
+		assert.ErrorContains(t, err, "pulumi config `peers[0].weight` failed on the `max` tag")
+		assert.NotContains(t, err.Error(), "peers[1]")
+	})
+}
+
+func TestGetConfigConfigKey(t *testing.T) {
+	type config struct {
+		SubscriptionID string `json:"subscriptionId" configKey:"subscription_id" validate:"required"`
+		Region         string `json:"region"`
+	}
+
+	runWithConfig(t, map[string]string{
+		"project:subscription_id": `"sub-123"`,
+		"project:subscriptionId":  `"ignored"`,
+		"project:region":          `"eu-west-1"`,
+	}, func(ctx *pulumi.Context) {
+		obj := &config{}
+		assert.NoError(t, GetConfig(ctx, obj))
+		assert.Equal(t, &config{SubscriptionID: "sub-123", Region: "eu-west-1"}, obj)
+
+		data, err := json.Marshal(obj)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"subscriptionId":"sub-123","region":"eu-west-1"}`, string(data))
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:subscriptionId": `"sub-123"`,
+	}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &config{})
+		assert.ErrorContains(t, err, "pulumi config `subscription_id`")
+	})
+}
+
+func TestValidateEach(t *testing.T) {
+	runWithConfig(t, map[string]string{}, func(ctx *pulumi.Context) {
+		configs := []TestDigitalOcean{
+			{Region: "us-east-1"},
+			{Region: "invalid"},
+			{Region: "eu-west-1"},
+		}
+		err := ValidateEach(ctx, configs)
+		assert.ErrorContains(t, err, "element 1:")
+		assert.NotContains(t, err.Error(), "element 0:")
+		assert.NotContains(t, err.Error(), "element 2:")
+
+		assert.NoError(t, ValidateEach(ctx, configs[:1]))
+
+		defaults := []TestDefaultValue{{}}
+		assert.NoError(t, ValidateEach(ctx, defaults))
+		assert.Equal(t, "DefaultValue", defaults[0].DefaultString)
+
+		assert.ErrorIs(t, ValidateEach(ctx, TestDigitalOcean{}), ErrUnsupportedType)
+	})
+}
+
+func TestGetConfigAs(t *testing.T) {
+	runWithConfig(t, map[string]string{
+		"project:digital_ocean":         `{"region":"us-east-1"}`,
+		"provider:provider_credentials": `{"token":"token123"}`,
+		"project:name":                  `"DeploymentName"`,
+	}, func(ctx *pulumi.Context) {
+		obj, err := GetConfigAs[TestPulumiConfig](ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, &TestPulumiConfig{
+			DigitalOcean:        TestDigitalOcean{Region: "us-east-1"},
+			ProviderCredentials: &TestProviderCredentials{Token: "token123"},
+			Name:                "DeploymentName",
+		}, obj)
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:name": `"DeploymentName"`,
+	}, func(ctx *pulumi.Context) {
+		obj, err := GetConfigAs[TestPulumiConfig](ctx)
+		assert.ErrorContains(t, err, "pulumi config `digital_ocean`")
+		assert.Nil(t, obj)
+	})
+}
+
+func TestReloadConfig(t *testing.T) {
+	obj := &TestPulumiConfig{}
+	runWithConfig(t, map[string]string{
+		"project:digital_ocean":         `{"region":"us-east-1"}`,
+		"provider:provider_credentials": `{"token":"token123"}`,
+		"project:subscription_id":       `"sub123"`,
+		"project:name":                  `"DeploymentName"`,
+	}, func(ctx *pulumi.Context) {
+		assert.NoError(t, GetConfig(ctx, obj))
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:digital_ocean":         `{"region":"eu-west-1"}`,
+		"provider:provider_credentials": `{"token":"token456"}`,
+		"project:name":                  `"DeploymentName"`,
+	}, func(ctx *pulumi.Context) {
+		assert.NoError(t, ReloadConfig(ctx, obj))
+	})
+
+	assert.Equal(t, &TestPulumiConfig{
+		DigitalOcean:        TestDigitalOcean{Region: "eu-west-1"},
+		ProviderCredentials: &TestProviderCredentials{Token: "token456"},
+		Name:                "DeploymentName",
+	}, obj)
+
+	runWithConfig(t, map[string]string{
+		"project:digital_ocean": `{"region":"invalid"}`,
+	}, func(ctx *pulumi.Context) {
+		assert.Error(t, ReloadConfig(ctx, obj))
+	})
+
+	assert.Equal(t, "eu-west-1", obj.DigitalOcean.Region, "Object should be unchanged after a failed reload")
+}
+
+func TestReloadConfigNonPointer(t *testing.T) {
+	runWithConfig(t, map[string]string{}, func(ctx *pulumi.Context) {
+		assert.ErrorIs(t, ReloadConfig(ctx, TestPulumiConfig{}), ErrUnsupportedType)
+		assert.ErrorIs(t, ReloadConfig(ctx, (*TestPulumiConfig)(nil)), ErrUnsupportedType)
+		assert.ErrorIs(t, ReloadConfig(ctx, nil), ErrUnsupportedType)
+	})
+}
@@ -0,0 +1,56 @@
+package pulumiconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+var (
+	// ErrConfigReferenceCycle is returned when config references refer back to themselves.
+	ErrConfigReferenceCycle = errors.New("config reference cycle")
+	// ErrInvalidConfigReference is returned when a config reference is malformed or can't be resolved.
+	ErrInvalidConfigReference = errors.New("invalid config reference")
+)
+
+const (
+	referencePrefix = "${ref:"
+	referenceSuffix = "}"
+)
+
+// resolveReference replaces a raw config value of the form `${ref:namespace:key}` with the raw value
+// of the referenced config key, following references to references until a regular value is found.
+// Any other value is returned as is.
+func resolveReference(ctx *pulumi.Context, raw string) (string, error) {
+	visited := map[string]bool{}
+	for {
+		var s string
+		if json.Unmarshal([]byte(raw), &s) != nil {
+			s = raw
+		}
+		if !strings.HasPrefix(s, referencePrefix) || !strings.HasSuffix(s, referenceSuffix) {
+			return raw, nil
+		}
+
+		reference := strings.TrimSuffix(strings.TrimPrefix(s, referencePrefix), referenceSuffix)
+		if visited[reference] {
+			return "", fmt.Errorf("%w: `%s`", ErrConfigReferenceCycle, reference)
+		}
+		visited[reference] = true
+
+		namespace, key, ok := strings.Cut(reference, ":")
+		if !ok || namespace == "" || key == "" {
+			return "", fmt.Errorf("%w: `%s`", ErrInvalidConfigReference, s)
+		}
+
+		var err error
+		raw, err = config.New(ctx, namespace).Try(key)
+		if err != nil {
+			return "", fmt.Errorf("%w: `%s`: %w", ErrInvalidConfigReference, s, err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package pulumiconfig
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestReference struct {
+	PrimaryRegion string            `json:"primary_region"`
+	BackupRegion  string            `json:"backup_region"`
+	DigitalOcean  *TestDigitalOcean `json:"digital_ocean"`
+}
+
+func TestGetConfigReference(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]string
+		want    *TestReference
+		wantErr error
+	}{
+		{
+			name: "field references another field",
+			config: map[string]string{
+				"project:primary_region": `"eu-west-1"`,
+				"project:backup_region":  `"${ref:project:primary_region}"`,
+				"project:digital_ocean":  `"${ref:shared:digital_ocean}"`,
+				"shared:digital_ocean":   `{"region":"us-east-1"}`,
+			},
+			want: &TestReference{
+				PrimaryRegion: "eu-west-1",
+				BackupRegion:  "eu-west-1",
+				DigitalOcean:  &TestDigitalOcean{Region: "us-east-1"},
+			},
+		},
+		{
+			name: "reference cycle",
+			config: map[string]string{
+				"project:primary_region": `"${ref:project:backup_region}"`,
+				"project:backup_region":  `"${ref:project:primary_region}"`,
+			},
+			wantErr: ErrConfigReferenceCycle,
+		},
+		{
+			name: "missing referenced key",
+			config: map[string]string{
+				"project:backup_region": `"${ref:project:unknown}"`,
+			},
+			wantErr: ErrInvalidConfigReference,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runWithConfig(t, tt.config, func(ctx *pulumi.Context) {
+				obj := &TestReference{}
+				err := GetConfig(ctx, obj)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+				} else {
+					assert.NoError(t, err)
+					assert.Equal(t, tt.want, obj)
+				}
+			})
+		})
+	}
+}
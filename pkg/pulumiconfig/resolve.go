@@ -0,0 +1,47 @@
+package pulumiconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// ResolveConfigMap resolves the config keys of the struct obj like GetConfig, without populating or
+// validating it, and returns the values that are set, keyed by `namespace:key` so the namespace each
+// value came from is visible, e.g. to show operators what a stack will consume. Values are decoded
+// from JSON when possible and kept as strings otherwise.
+func ResolveConfigMap(ctx *pulumi.Context, obj interface{}) (map[string]interface{}, error) {
+	t := reflect.TypeOf(obj)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	values := map[string]interface{}{}
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i)
+		keys := getConfigKeys(fieldType)
+		if fieldType.Tag.Get("source") != "" || len(keys) == 0 {
+			continue
+		}
+
+		cfg := config.New(ctx, fieldType.Tag.Get("pulumiConfigNamespace"))
+		key, index := resolveConfigKey(cfg, keys)
+		if index < 0 {
+			continue
+		}
+		raw, err := resolveReference(ctx, cfg.Get(key))
+		if err != nil {
+			return nil, fmt.Errorf("Error while reading pulumi config `%s`: %w", key, err)
+		}
+
+		var value interface{}
+		if json.Unmarshal([]byte(raw), &value) != nil {
+			value = raw
+		}
+		values[configFullKey(ctx, fieldType, key)] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,32 @@
+package pulumiconfig
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveConfigMap(t *testing.T) {
+	runWithConfig(t, map[string]string{
+		"project:digital_ocean":         `{"region":"us-east-1"}`,
+		"provider:provider_credentials": `{"token":"token123"}`,
+		"project:name":                  `"${ref:project:deployment}"`,
+		"project:deployment":            `"DeploymentName"`,
+		"project:org_id":                `42`,
+		"project:legacy_region":         `eu-west-1`,
+	}, func(ctx *pulumi.Context) {
+		values, err := ResolveConfigMap(ctx, &TestPulumiConfig{})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"project:digital_ocean":         map[string]interface{}{"region": "us-east-1"},
+			"provider:provider_credentials": map[string]interface{}{"token": "token123"},
+			"project:name":                  "DeploymentName",
+			"project:org_id":                float64(42),
+		}, values)
+
+		values, err = ResolveConfigMap(ctx, TestConfigKeys{})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"project:legacy_region": "eu-west-1"}, values)
+	})
+}
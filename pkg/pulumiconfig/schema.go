@@ -0,0 +1,42 @@
+package pulumiconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// schemaURL is the resource name under which the provided schema is registered with the compiler.
+const schemaURL = "pulumiconfig.schema.json"
+
+// ValidateAgainstSchema marshals the populated config struct to JSON and validates it against the provided JSON Schema.
+// This allows enforcing organisation-wide schemas independent of the struct's validation tags.
+func ValidateAgainstSchema(obj interface{}, schema []byte) error {
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaURL, bytes.NewReader(schema)); err != nil {
+		return fmt.Errorf("Error while loading JSON schema: %w", err)
+	}
+
+	compiled, err := compiler.Compile(schemaURL)
+	if err != nil {
+		return fmt.Errorf("Error while compiling JSON schema: %w", err)
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("Error while marshaling config to JSON: %w", err)
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("Error while unmarshaling config JSON: %w", err)
+	}
+
+	if err := compiled.Validate(doc); err != nil {
+		return fmt.Errorf("Schema validation error: %w", err)
+	}
+
+	return nil
+}
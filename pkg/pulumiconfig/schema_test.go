@@ -0,0 +1,67 @@
+package pulumiconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAgainstSchema(t *testing.T) {
+	obj := &TestPulumiConfig{
+		DigitalOcean: TestDigitalOcean{Region: "us-east-1"},
+		ProviderCredentials: &TestProviderCredentials{
+			Token: "token123",
+		},
+		OrgID: 123,
+		Name:  "DeploymentName",
+	}
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr bool
+	}{
+		{
+			name: "config matches schema",
+			schema: `{
+				"type": "object",
+				"required": ["digital_ocean", "name"],
+				"properties": {
+					"digital_ocean": {
+						"type": "object",
+						"properties": {"region": {"enum": ["us-east-1", "eu-west-1"]}}
+					},
+					"org_id": {"type": "integer", "minimum": 1}
+				}
+			}`,
+			wantErr: false,
+		},
+		{
+			name: "config does not match schema",
+			schema: `{
+				"type": "object",
+				"properties": {
+					"digital_ocean": {
+						"type": "object",
+						"properties": {"region": {"enum": ["eu-west-1"]}}
+					}
+				}
+			}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid schema",
+			schema:  `{"type": 1}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAgainstSchema(obj, []byte(tt.schema))
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
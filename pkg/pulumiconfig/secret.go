@@ -0,0 +1,94 @@
+package pulumiconfig
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// redactedSecret is printed in place of the value of a Secret.
+const redactedSecret = "[secret]"
+
+// Secret is a string config value that is redacted when printed or marshaled to JSON, so logging a
+// config struct doesn't leak it. Use Value to access the actual value.
+type Secret string
+
+// Value returns the actual value of the secret.
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// String returns a redacted placeholder instead of the value of the secret.
+func (s Secret) String() string {
+	return redactedSecret
+}
+
+// GoString returns a redacted placeholder instead of the value of the secret.
+func (s Secret) GoString() string {
+	return redactedSecret
+}
+
+// MarshalJSON encodes a redacted placeholder instead of the value of the secret, so structured
+// loggers don't leak it either.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redactedSecret)
+}
+
+// getSecretOutput populates a pulumi.StringOutput field tagged `secret:"true"` with the config value
+// as a secret output, so it stays marked secret wherever it is used.
+// A missing value is only an error for required fields.
+func getSecretOutput(cfg *config.Config, jsonTag string, field reflect.Value, isRequired bool) error {
+	output, err := cfg.TrySecret(jsonTag)
+	if err != nil {
+		if isRequired {
+			return &MissingRequiredFieldError{Field: jsonTag, Err: err}
+		}
+		return nil
+	}
+
+	// Config values are JSON encoded, but plain strings are accepted as well.
+	decoded := output.ApplyT(func(raw string) string {
+		var s string
+		if json.Unmarshal([]byte(raw), &s) != nil {
+			return raw
+		}
+		return s
+	})
+	field.Set(reflect.ValueOf(decoded))
+	return nil
+}
+
+// setSecretOutput sets a pulumi.StringOutput field to the raw config value as a secret output.
+// Config values are JSON encoded, but plain strings are accepted as well.
+func setSecretOutput(ctx *pulumi.Context, raw string, field reflect.Value) {
+	var s string
+	if json.Unmarshal([]byte(raw), &s) != nil {
+		s = raw
+	}
+	output := pulumi.ToSecretWithContext(ctx.Context(), pulumi.String(s))
+	field.Set(reflect.ValueOf(output))
+}
+
+// isSecretOutputField reports whether the field is a pulumi.StringOutput tagged `secret:"true"`.
+func isSecretOutputField(field reflect.Value, tag reflect.StructTag) bool {
+	return tag.Get("secret") == "true" && field.Type() == reflect.TypeOf(pulumi.StringOutput{})
+}
+
+// isSecretConfig reports whether the value of the field, read from the given `namespace:key`, must be
+// kept secret. This is the case for secret config values and for fields of the Secret type or tagged
+// `secret:"true"`.
+func isSecretConfig(ctx *pulumi.Context, fieldType reflect.StructField, fullKey string) bool {
+	return ctx.IsConfigSecret(fullKey) || fieldType.Tag.Get("secret") == "true" || fieldType.Type == reflect.TypeOf(Secret(""))
+}
+
+// configFullKey returns the `namespace:key` of a config key of the field, using the project as the
+// namespace unless the field has a `pulumiConfigNamespace` tag.
+func configFullKey(ctx *pulumi.Context, fieldType reflect.StructField, key string) string {
+	namespace := fieldType.Tag.Get("pulumiConfigNamespace")
+	if namespace == "" {
+		namespace = ctx.Project()
+	}
+	return namespace + ":" + key
+}
@@ -0,0 +1,47 @@
+package pulumiconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/internals"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestSecret struct {
+	Token    Secret              `json:"token" validate:"required"`
+	Password pulumi.StringOutput `json:"password" secret:"true" validate:"required"`
+}
+
+func TestGetConfigSecret(t *testing.T) {
+	runWithConfig(t, map[string]string{
+		"project:token":    `"s3cr3t"`,
+		"project:password": `"p4ssw0rd"`,
+	}, func(ctx *pulumi.Context) {
+		obj := &TestSecret{}
+		assert.NoError(t, GetConfig(ctx, obj))
+
+		assert.Equal(t, "s3cr3t", obj.Token.Value())
+		assert.Equal(t, redactedSecret, obj.Token.String())
+		assert.NotContains(t, fmt.Sprintf("%v %+v %#v", obj.Token, *obj, *obj), "s3cr3t")
+
+		marshaled, err := json.Marshal(struct{ Token Secret }{obj.Token})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"Token":"[secret]"}`, string(marshaled))
+
+		result, err := internals.UnsafeAwaitOutput(context.Background(), obj.Password)
+		assert.NoError(t, err)
+		assert.Equal(t, "p4ssw0rd", result.Value)
+		assert.True(t, result.Secret)
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:token": `"s3cr3t"`,
+	}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &TestSecret{})
+		assert.ErrorContains(t, err, "pulumi config `password`")
+	})
+}
@@ -0,0 +1,171 @@
+package pulumiconfig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	// ErrInvalidSemver is returned when a value is not a valid semantic version.
+	ErrInvalidSemver = errors.New("invalid semantic version")
+)
+
+// semVersion is a parsed semantic version. Build metadata is ignored as it has no precedence.
+type semVersion struct {
+	core       [3]uint64 // The major, minor and patch versions.
+	prerelease []string  // The dot separated pre-release identifiers, if any.
+}
+
+// parseSemver parses a semantic version such as `1.2.3`, `v1.2.3-rc.1` or `1.2.3+build.5`.
+func parseSemver(s string) (semVersion, error) {
+	var v semVersion
+	version := strings.TrimPrefix(s, "v")
+	version, _, _ = strings.Cut(version, "+")
+	version, prerelease, hasPrerelease := strings.Cut(version, "-")
+
+	parts := strings.Split(version, ".")
+	if len(parts) != len(v.core) {
+		return semVersion{}, fmt.Errorf("%w: `%s`", ErrInvalidSemver, s)
+	}
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil || (len(part) > 1 && part[0] == '0') {
+			return semVersion{}, fmt.Errorf("%w: `%s`", ErrInvalidSemver, s)
+		}
+		v.core[i] = n
+	}
+
+	if hasPrerelease {
+		v.prerelease = strings.Split(prerelease, ".")
+		for _, identifier := range v.prerelease {
+			if identifier == "" {
+				return semVersion{}, fmt.Errorf("%w: `%s`", ErrInvalidSemver, s)
+			}
+		}
+	}
+	return v, nil
+}
+
+// compare returns -1, 0 or 1 depending on whether v has a lower, equal or higher precedence than o.
+// A pre-release version has a lower precedence than the associated normal version.
+func (v semVersion) compare(o semVersion) int {
+	for i := range v.core {
+		if c := compareUint(v.core[i], o.core[i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(v.prerelease) == 0 && len(o.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(o.prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.prerelease) && i < len(o.prerelease); i++ {
+		if c := comparePrereleaseIdentifier(v.prerelease[i], o.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareUint(uint64(len(v.prerelease)), uint64(len(o.prerelease)))
+}
+
+// comparePrereleaseIdentifier compares two pre-release identifiers. Numeric identifiers are compared
+// numerically and have a lower precedence than alphanumeric identifiers, which are compared lexically.
+func comparePrereleaseIdentifier(a, b string) int {
+	na, errA := strconv.ParseUint(a, 10, 64)
+	nb, errB := strconv.ParseUint(b, 10, 64)
+	switch {
+	case errA == nil && errB == nil:
+		return compareUint(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// compareUint returns -1, 0 or 1 depending on whether a is lower, equal or higher than b.
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// satisfiesSemverRange reports whether v satisfies all space separated comparators of the range,
+// e.g. `>=1.2.0 <2.0.0`. Supported operators are `=`, `>`, `>=`, `<` and `<=`.
+// A pre-release version only satisfies the range if a comparator has a pre-release on the same
+// major, minor and patch version, so ranges don't match pre-releases by accident.
+func satisfiesSemverRange(v semVersion, semverRange string) (bool, error) {
+	prereleaseAllowed := len(v.prerelease) == 0
+	for _, comparator := range strings.Fields(semverRange) {
+		operator := strings.TrimRight(comparator, "v0123456789.-+abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+		bound, err := parseSemver(strings.TrimPrefix(comparator, operator))
+		if err != nil {
+			return false, err
+		}
+		if len(bound.prerelease) > 0 && bound.core == v.core {
+			prereleaseAllowed = true
+		}
+
+		c := v.compare(bound)
+		var ok bool
+		switch operator {
+		case "", "=":
+			ok = c == 0
+		case ">":
+			ok = c > 0
+		case ">=":
+			ok = c >= 0
+		case "<":
+			ok = c < 0
+		case "<=":
+			ok = c <= 0
+		default:
+			return false, fmt.Errorf("%w: unknown operator in `%s`", ErrInvalidSemver, comparator)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return prereleaseAllowed, nil
+}
+
+// semverStable is a validator function that checks a string is a semantic version without a pre-release.
+func semverStable(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.String {
+		return true
+	}
+	v, err := parseSemver(field.String())
+	return err == nil && len(v.prerelease) == 0
+}
+
+// semverRange is a validator function that checks a string is a semantic version within the range of
+// the tag parameter, e.g. `semverrange=>=1.2.0 <2.0.0`. See satisfiesSemverRange for the range syntax.
+func semverRange(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.String {
+		return true
+	}
+	v, err := parseSemver(field.String())
+	if err != nil {
+		return false
+	}
+	ok, err := satisfiesSemverRange(v, fl.Param())
+	return err == nil && ok
+}
@@ -0,0 +1,81 @@
+package pulumiconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_semVersion_compare(t *testing.T) {
+	// Ordered by increasing precedence, as in the semantic versioning specification.
+	versions := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0",
+	}
+	for i := 0; i < len(versions)-1; i++ {
+		lower, err := parseSemver(versions[i])
+		assert.NoError(t, err)
+		higher, err := parseSemver(versions[i+1])
+		assert.NoError(t, err)
+		assert.Equal(t, -1, lower.compare(higher), "%s < %s", versions[i], versions[i+1])
+		assert.Equal(t, 1, higher.compare(lower), "%s > %s", versions[i+1], versions[i])
+	}
+
+	_, err := parseSemver("1.02.0")
+	assert.ErrorIs(t, err, ErrInvalidSemver)
+}
+
+func Test_semver(t *testing.T) {
+	type config struct {
+		Version string `validate:"semverstable"`
+		Chart   string `validate:"semverrange=>=1.2.0 <2.0.0"`
+		Beta    string `validate:"semverrange=>=2.0.0-beta.1 <2.0.0"`
+	}
+	tests := []struct {
+		name    string
+		value   config
+		wantErr bool
+	}{
+		{
+			name:    "stable versions within range",
+			value:   config{Version: "v1.4.2", Chart: "1.9.9", Beta: "2.0.0-beta.2"},
+			wantErr: false,
+		},
+		{
+			name:    "pre-release rejected by semverstable",
+			value:   config{Version: "1.4.2-rc.1", Chart: "1.9.9", Beta: "2.0.0-beta.2"},
+			wantErr: true,
+		},
+		{
+			name:    "range excludes pre-releases of other versions",
+			value:   config{Version: "1.4.2", Chart: "1.5.0-rc.1", Beta: "2.0.0-beta.2"},
+			wantErr: true,
+		},
+		{
+			name:    "range includes pre-releases above its pre-release bound",
+			value:   config{Version: "1.4.2", Chart: "1.2.0", Beta: "2.0.0-rc.1"},
+			wantErr: false,
+		},
+		{
+			name:    "pre-release below the range",
+			value:   config{Version: "1.4.2", Chart: "1.2.0", Beta: "2.0.0-alpha"},
+			wantErr: true,
+		},
+		{
+			name:    "version outside range",
+			value:   config{Version: "1.4.2", Chart: "2.0.0", Beta: "2.0.0-beta.2"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := tt.value
+			err := validateStruct(t, &value)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
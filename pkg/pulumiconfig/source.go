@@ -0,0 +1,98 @@
+package pulumiconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrUnknownConfigSource is returned when a field references a config source that is not registered.
+	ErrUnknownConfigSource = errors.New("unknown config source")
+)
+
+// ConfigSource supplies config values from an external backend such as Vault or AWS SSM.
+// Fields opt in with a `source` tag of the form `source:"<name>:<reference>"`, e.g.
+// `source:"vault:secret/data/app#token"`, where the reference is passed to Resolve as is.
+type ConfigSource interface {
+	Resolve(reference string) (string, error)
+}
+
+// ContextConfigSource is a ConfigSource that supports cancellation. GetConfig calls ResolveContext
+// instead of Resolve with the context of WithContext, or the context of the Pulumi program.
+type ContextConfigSource interface {
+	ConfigSource
+	ResolveContext(ctx context.Context, reference string) (string, error)
+}
+
+//nolint:gochecknoglobals // registry shared by all GetConfig calls
+var (
+	configSourcesMu sync.RWMutex
+	configSources   = map[string]ConfigSource{}
+)
+
+// RegisterConfigSource registers a config source under the given name, replacing any source
+// previously registered under the same name.
+func RegisterConfigSource(name string, source ConfigSource) {
+	configSourcesMu.Lock()
+	defer configSourcesMu.Unlock()
+	configSources[name] = source
+}
+
+// getConfigSource returns the config source registered under the given name, if any.
+func getConfigSource(name string) (ConfigSource, bool) {
+	configSourcesMu.RLock()
+	defer configSourcesMu.RUnlock()
+	source, ok := configSources[name]
+	return source, ok
+}
+
+// getSourceValue resolves the `source` tag reference through the named config source and stores
+// the value in the field. String fields receive the value verbatim, other fields decode it as JSON.
+// The lookup is abandoned with the error of the context when the context is done first.
+func getSourceValue(ctx context.Context, tag string, field reflect.Value) error {
+	name, reference, _ := strings.Cut(tag, ":")
+	source, ok := getConfigSource(name)
+	if !ok {
+		return fmt.Errorf("Error while reading config source `%s`: %w", tag, ErrUnknownConfigSource)
+	}
+
+	value, err := resolveSource(ctx, source, reference)
+	if err != nil {
+		return fmt.Errorf("Error while reading config source `%s`: %w", tag, err)
+	}
+
+	if err := decodePlainValue(value, field); err != nil {
+		return fmt.Errorf("Error while reading config source `%s`: %w", tag, err)
+	}
+	return nil
+}
+
+// resolveSource resolves the reference through the source, returning the error of the context when
+// it is done before a source without cancellation support returns.
+func resolveSource(ctx context.Context, source ConfigSource, reference string) (string, error) {
+	if contextSource, ok := source.(ContextConfigSource); ok {
+		return contextSource.ResolveContext(ctx, reference)
+	}
+
+	type result struct {
+		value string
+		err   error
+	}
+	// The channel is buffered so the lookup can finish after it was abandoned.
+	results := make(chan result, 1)
+	go func() {
+		value, err := source.Resolve(reference)
+		results <- result{value: value, err: err}
+	}()
+
+	select {
+	case r := <-results:
+		return r.value, r.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
@@ -0,0 +1,121 @@
+package pulumiconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+// errReferenceNotFound is returned by fakeSource for unknown references.
+var errReferenceNotFound = errors.New("reference not found")
+
+// fakeSource is a ConfigSource that resolves references from an in-memory map.
+type fakeSource map[string]string
+
+// Resolve returns the value stored for the reference.
+func (s fakeSource) Resolve(reference string) (string, error) {
+	value, ok := s[reference]
+	if !ok {
+		return "", fmt.Errorf("%w: `%s`", errReferenceNotFound, reference)
+	}
+	return value, nil
+}
+
+type TestSource struct {
+	Token string `json:"token" source:"vault:secret/data/app#token" validate:"required"`
+	Port  int    `json:"port" source:"vault:secret/data/app#port"`
+	Name  string `json:"name"`
+}
+
+type TestUnknownSource struct {
+	Token string `json:"token" source:"ssm:/app/token"`
+}
+
+// restoreConfigSources resets the config source registry to its current state when the test ends,
+// so sources registered by the test don't leak into other tests.
+func restoreConfigSources(t *testing.T) {
+	configSourcesMu.RLock()
+	saved := make(map[string]ConfigSource, len(configSources))
+	for name, source := range configSources {
+		saved[name] = source
+	}
+	configSourcesMu.RUnlock()
+
+	t.Cleanup(func() {
+		configSourcesMu.Lock()
+		defer configSourcesMu.Unlock()
+		configSources = saved
+	})
+}
+
+func TestGetConfigSource(t *testing.T) {
+	restoreConfigSources(t)
+	RegisterConfigSource("vault", fakeSource{
+		"secret/data/app#token": "s3cr3t",
+		"secret/data/app#port":  "8080",
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:token": `"from-pulumi"`,
+		"project:name":  `"DeploymentName"`,
+	}, func(ctx *pulumi.Context) {
+		obj := &TestSource{}
+		assert.NoError(t, GetConfig(ctx, obj))
+		assert.Equal(t, &TestSource{Token: "s3cr3t", Port: 8080, Name: "DeploymentName"}, obj)
+
+		err := GetConfig(ctx, &TestUnknownSource{})
+		assert.ErrorIs(t, err, ErrUnknownConfigSource)
+		assert.ErrorContains(t, err, "ssm:/app/token")
+	})
+}
+
+// blockingSource is a ConfigSource that blocks until it is released.
+type blockingSource chan struct{}
+
+// Resolve blocks until the source is released.
+func (s blockingSource) Resolve(_ string) (string, error) {
+	<-s
+	return "late", nil
+}
+
+// cancellableSource is a ContextConfigSource that blocks until its context is done.
+type cancellableSource struct{}
+
+// Resolve is not used, since GetConfig prefers ResolveContext.
+func (cancellableSource) Resolve(_ string) (string, error) {
+	return "", errReferenceNotFound
+}
+
+// ResolveContext blocks until the context is done.
+func (cancellableSource) ResolveContext(ctx context.Context, _ string) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestGetConfigSourceTimeout(t *testing.T) {
+	release := make(blockingSource)
+	defer close(release)
+	restoreConfigSources(t)
+	RegisterConfigSource("slow", release)
+	RegisterConfigSource("esc", cancellableSource{})
+
+	type config struct {
+		Token  string `json:"token" source:"slow:app/token"`
+		Secret string `json:"secret" source:"esc:app/secret"`
+	}
+
+	runWithConfig(t, map[string]string{}, func(ctx *pulumi.Context) {
+		timeout, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := GetConfig(ctx, &config{}, WithContext(timeout))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, "Error while reading config source `slow:app/token`")
+		assert.ErrorContains(t, err, "Error while reading config source `esc:app/secret`")
+	})
+}
@@ -0,0 +1,90 @@
+package pulumiconfig
+
+import (
+	"errors"
+	"reflect"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// stackTagName returns the name of the tag holding the validation rules for the current stack,
+// e.g. `validateProd` for the `prod` stack.
+func stackTagName(ctx *pulumi.Context) string {
+	if ctx == nil || ctx.Stack() == "" {
+		return ""
+	}
+	stack := []rune(ctx.Stack())
+	stack[0] = unicode.ToUpper(stack[0])
+	return "validate" + string(stack)
+}
+
+// getStackFields returns the namespaces, as used by the validator, of all fields of the struct type t
+// and its nested structs that have rules in the given stack tag.
+func getStackFields(t reflect.Type, stackTag string) map[string]bool {
+	fields := map[string]bool{}
+	if stackTag == "" {
+		return fields
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Name() != "" {
+		collectStackFields(t, t.Name()+".", stackTag, fields)
+	} else {
+		collectStackFields(t, "", stackTag, fields)
+	}
+	return fields
+}
+
+// collectStackFields adds the namespaces of the fields of t with rules in the given stack tag to fields.
+func collectStackFields(t reflect.Type, prefix, stackTag string, fields map[string]bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if _, ok := field.Tag.Lookup(stackTag); ok {
+			fields[prefix+field.Name] = true
+			continue
+		}
+		collectStackFields(field.Type, prefix+field.Name+".", stackTag, fields)
+	}
+}
+
+// validateStack validates obj, replacing the `validate` rules of fields that have rules in the stack
+// tag with those rules. Struct-level validations only run once, with the `validate` rules.
+func validateStack(ctx *pulumi.Context, validate *validator.Validate, obj interface{}, validators []Validator) error {
+	stackTag := stackTagName(ctx)
+	stackFields := getStackFields(reflect.TypeOf(obj), stackTag)
+	if len(stackFields) == 0 {
+		return withConfigPaths(validate.Struct(obj), obj)
+	}
+
+	err := validate.StructFiltered(obj, func(ns []byte) bool {
+		return stackFields[string(ns)]
+	})
+
+	stackValidate := validator.New()
+	stackValidate.SetTagName(stackTag)
+	stackValidate.RegisterTagNameFunc(configKeyName)
+	for _, v := range validators {
+		switch v.(type) {
+		case StructValidation, StructValidations:
+			continue
+		}
+		if regErr := v.Register(stackValidate); regErr != nil {
+			return regErr
+		}
+	}
+
+	return errors.Join(withConfigPaths(err, obj), withConfigPaths(stackValidate.Struct(obj), obj))
+}
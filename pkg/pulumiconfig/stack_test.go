@@ -0,0 +1,55 @@
+package pulumiconfig
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestStackRules struct {
+	Name     string          `json:"name" validate:"required" validateProd:"required,min=3"`
+	Replicas int             `json:"replicas" validate:"min=1" validateProd:"min=3"`
+	Database TestStackNested `json:"database"`
+}
+
+type TestStackNested struct {
+	Size string `json:"size" validate:"oneof=small medium large" validateProd:"oneof=large"`
+}
+
+func TestGetConfigStackRules(t *testing.T) {
+	config := map[string]string{
+		"project:name":     `"ab"`,
+		"project:replicas": `1`,
+		"project:database": `{"size":"small"}`,
+	}
+
+	runWithStackConfig(t, "dev", config, func(ctx *pulumi.Context) {
+		assert.NoError(t, GetConfig(ctx, &TestStackRules{}))
+	})
+
+	runWithStackConfig(t, "prod", config, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &TestStackRules{})
+		assert.ErrorContains(t, err, "pulumi config `name` failed on the `min` tag")
+		assert.ErrorContains(t, err, "pulumi config `replicas` failed on the `min` tag")
+		assert.ErrorContains(t, err, "pulumi config `database.size` failed on the `oneof` tag")
+	})
+
+	runWithStackConfig(t, "prod", map[string]string{
+		"project:name":     `"abc"`,
+		"project:replicas": `3`,
+		"project:database": `{"size":"large"}`,
+	}, func(ctx *pulumi.Context) {
+		assert.NoError(t, GetConfig(ctx, &TestStackRules{}))
+	})
+
+	runWithStackConfig(t, "dev", map[string]string{
+		"project:name":     `"ab"`,
+		"project:replicas": `0`,
+		"project:database": `{"size":"huge"}`,
+	}, func(ctx *pulumi.Context) {
+		err := GetConfig(ctx, &TestStackRules{})
+		assert.ErrorContains(t, err, "pulumi config `replicas` failed on the `min` tag")
+		assert.ErrorContains(t, err, "pulumi config `database.size` failed on the `oneof` tag")
+	})
+}
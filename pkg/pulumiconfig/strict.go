@@ -0,0 +1,84 @@
+package pulumiconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+var (
+	// ErrUnknownConfigKey is returned in strict mode for config keys without a matching field.
+	ErrUnknownConfigKey = errors.New("unknown config key")
+)
+
+// WithStrictKeys makes GetConfig report config keys that have no matching field, e.g. a typo like
+// `reigon` for `region`. Only the namespaces read by the struct are checked, so keys of other
+// namespaces, such as provider settings, are ignored.
+func WithStrictKeys() Option {
+	return func(o *options) {
+		o.strictKeys = true
+	}
+}
+
+// checkUnknownKeys returns an error for every config key in a namespace read by the struct type t
+// that doesn't match any of its fields. The SDK doesn't list config keys, so they are read from the
+// raw config in the environment.
+func checkUnknownKeys(ctx *pulumi.Context, t reflect.Type) error {
+	rawConfig := map[string]string{}
+	if env := os.Getenv(pulumi.EnvConfig); env != "" {
+		if err := json.Unmarshal([]byte(env), &rawConfig); err != nil {
+			return fmt.Errorf("Error while reading the raw pulumi config: %w", err)
+		}
+	}
+
+	known := knownConfigKeys(ctx, t)
+	var unknown []string
+	for fullKey := range rawConfig {
+		namespace, key, ok := strings.Cut(fullKey, ":")
+		if !ok {
+			continue
+		}
+		if keys, consumed := known[namespace]; consumed && !keys[key] {
+			unknown = append(unknown, fullKey)
+		}
+	}
+	sort.Strings(unknown)
+
+	errs := make([]error, 0, len(unknown))
+	for _, fullKey := range unknown {
+		errs = append(errs, fmt.Errorf("pulumi config `%s`: %w", fullKey, ErrUnknownConfigKey))
+	}
+	return errors.Join(errs...)
+}
+
+// knownConfigKeys returns the config keys of all fields of the struct type t, grouped by namespace.
+func knownConfigKeys(ctx *pulumi.Context, t reflect.Type) map[string]map[string]bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	known := map[string]map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get("source") != "" {
+			continue
+		}
+		namespace := field.Tag.Get("pulumiConfigNamespace")
+		if namespace == "" {
+			namespace = ctx.Project()
+		}
+		if known[namespace] == nil {
+			known[namespace] = map[string]bool{}
+		}
+		for _, key := range getConfigKeys(field) {
+			known[namespace][key] = true
+		}
+	}
+	return known
+}
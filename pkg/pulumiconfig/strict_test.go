@@ -0,0 +1,47 @@
+package pulumiconfig
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithStrictKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]string
+		wantErr string
+	}{
+		{
+			name: "typo in config key",
+			config: map[string]string{
+				"project:digital_ocean":         `{"region":"us-east-1"}`,
+				"provider:provider_credentials": `{"token":"token123"}`,
+				"project:nmae":                  `"DeploymentName"`,
+			},
+			wantErr: "pulumi config `project:nmae`: unknown config key",
+		},
+		{
+			name: "keys of other namespaces are ignored",
+			config: map[string]string{
+				"project:digital_ocean":         `{"region":"us-east-1"}`,
+				"provider:provider_credentials": `{"token":"token123"}`,
+				"aws:region":                    `"us-east-1"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runWithConfig(t, tt.config, func(ctx *pulumi.Context) {
+				err := GetConfig(ctx, &TestPulumiConfig{}, WithStrictKeys())
+				if tt.wantErr != "" {
+					assert.ErrorContains(t, err, tt.wantErr)
+					assert.ErrorIs(t, err, ErrUnknownConfigKey)
+				} else {
+					assert.NoError(t, err)
+				}
+			})
+		})
+	}
+}
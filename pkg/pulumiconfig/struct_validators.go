@@ -0,0 +1,147 @@
+package pulumiconfig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	// ErrUnknownStructField is returned when a struct validation uses a field the struct doesn't have.
+	ErrUnknownStructField = errors.New("unknown struct field")
+)
+
+// AllOrNone returns a struct-level validation for the given struct type that fails when some,
+// but not all, of the named fields are set. Fields are referenced by their Go field names, and
+// registering the validation fails with ErrUnknownStructField if the struct has no such field.
+func AllOrNone(structType interface{}, fields ...string) StructValidation {
+	return StructValidation{
+		Struct: structType,
+		Validate: func(sl validator.StructLevel) {
+			current := sl.Current()
+
+			var unset []string
+			for _, name := range fields {
+				if current.FieldByName(name).IsZero() {
+					unset = append(unset, name)
+				}
+			}
+
+			if len(unset) == 0 || len(unset) == len(fields) {
+				return
+			}
+			for _, name := range unset {
+				sl.ReportError(current.FieldByName(name).Interface(), name, name, "all_or_none", strings.Join(fields, " "))
+			}
+		},
+		fields: fields,
+	}
+}
+
+// SumLTE returns a struct-level validation for the given struct type that fails when the sum of the
+// named numeric part fields exceeds the named numeric total field, e.g. `cpu + reserved_cpu <= total_cpu`.
+// Fields are referenced by their Go field names, and registering the validation fails with
+// ErrUnknownStructField if the struct has no such field.
+func SumLTE(structType interface{}, total string, parts ...string) StructValidation {
+	return StructValidation{
+		Struct: structType,
+		Validate: func(sl validator.StructLevel) {
+			current := sl.Current()
+
+			var sum float64
+			for _, name := range parts {
+				sum += numericValue(current.FieldByName(name))
+			}
+
+			totalField := current.FieldByName(total)
+			if sum > numericValue(totalField) {
+				sl.ReportError(totalField.Interface(), total, total, "sum_lte", strings.Join(parts, " "))
+			}
+		},
+		fields: append([]string{total}, parts...),
+	}
+}
+
+// TimeBefore returns a struct-level validation for the given struct type that fails when the named
+// start field is not strictly before the named end field. The fields can be times, pointers to times
+// or RFC3339 strings, and are referenced by their Go field names. Unset fields are not compared.
+// Registering the validation fails with ErrUnknownStructField if the struct has no such field.
+func TimeBefore(structType interface{}, startField, endField string) StructValidation {
+	return StructValidation{
+		Struct: structType,
+		Validate: func(sl validator.StructLevel) {
+			current := sl.Current()
+
+			start, startOK := timeValue(current.FieldByName(startField))
+			end, endOK := timeValue(current.FieldByName(endField))
+			if !startOK || !endOK {
+				return
+			}
+			if !start.Before(end) {
+				sl.ReportError(current.FieldByName(endField).Interface(), endField, endField, "time_before", startField)
+			}
+		},
+		fields: []string{startField, endField},
+	}
+}
+
+// timeValue returns the time of a time, pointer to time or RFC3339 string field, and whether it is set.
+func timeValue(field reflect.Value) (time.Time, bool) {
+	if !field.IsValid() {
+		return time.Time{}, false
+	}
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return time.Time{}, false
+		}
+		field = field.Elem()
+	}
+
+	switch {
+	case isTimeType(field.Type()):
+		t, _ := field.Interface().(time.Time)
+		return t, !t.IsZero()
+	case field.Kind() == reflect.String:
+		t, err := time.Parse(time.RFC3339, field.String())
+		return t, err == nil
+	default:
+		return time.Time{}, false
+	}
+}
+
+// numericValue returns the value of an integer, unsigned integer or float field as a float64.
+// Fields of any other kind are treated as zero.
+func numericValue(field reflect.Value) float64 {
+	switch field.Kind() { //nolint:exhaustive // non-numeric kinds are treated as zero
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(field.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(field.Uint())
+	case reflect.Float32, reflect.Float64:
+		return field.Float()
+	default:
+		return 0
+	}
+}
+
+// checkStructFields returns an error if the struct type has no field with one of the given names.
+func checkStructFields(structType interface{}, names []string) error {
+	t := reflect.TypeOf(structType)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for _, name := range names {
+		if _, ok := t.FieldByName(name); !ok {
+			return fmt.Errorf("struct `%s` has no field `%s`: %w", t, name, ErrUnknownStructField)
+		}
+	}
+	return nil
+}
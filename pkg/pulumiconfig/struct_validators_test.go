@@ -0,0 +1,195 @@
+package pulumiconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestCredentials struct {
+	AccessKey string `json:"access_key"`
+	SecretKey string `json:"secret_key"`
+}
+
+type TestQuota struct {
+	CPU         int     `json:"cpu"`
+	ReservedCPU float64 `json:"reserved_cpu"`
+	TotalCPU    uint    `json:"total_cpu"`
+}
+
+type TestMaintenanceWindow struct {
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end"`
+}
+
+type TestPlacement struct {
+	AvailabilityZone string `json:"availability_zone"`
+	Region           string `json:"region" validate:"required"`
+}
+
+func TestAllOrNone(t *testing.T) {
+	tests := []struct {
+		name    string
+		obj     *TestCredentials
+		wantErr bool
+	}{
+		{
+			name:    "all fields are set",
+			obj:     &TestCredentials{AccessKey: "access", SecretKey: "secret"},
+			wantErr: false,
+		},
+		{
+			name:    "no fields are set",
+			obj:     &TestCredentials{},
+			wantErr: false,
+		},
+		{
+			name:    "some fields are set",
+			obj:     &TestCredentials{AccessKey: "access"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validate := validator.New()
+			err := registerValidations(validate, []Validator{AllOrNone(TestCredentials{}, "AccessKey", "SecretKey")})
+			assert.NoError(t, err)
+
+			err = validate.Struct(tt.obj)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "SecretKey")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAllOrNoneUnknownField(t *testing.T) {
+	err := registerValidations(validator.New(), []Validator{AllOrNone(TestCredentials{}, "AccessKey", "SecretKy")})
+	assert.ErrorIs(t, err, ErrUnknownStructField)
+	assert.ErrorContains(t, err, "SecretKy")
+}
+
+func TestSumLTE(t *testing.T) {
+	tests := []struct {
+		name    string
+		obj     *TestQuota
+		wantErr bool
+	}{
+		{
+			name:    "within budget",
+			obj:     &TestQuota{CPU: 4, ReservedCPU: 2, TotalCPU: 6},
+			wantErr: false,
+		},
+		{
+			name:    "over budget",
+			obj:     &TestQuota{CPU: 4, ReservedCPU: 2.5, TotalCPU: 6},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validate := validator.New()
+			err := registerValidations(validate, []Validator{SumLTE(TestQuota{}, "TotalCPU", "CPU", "ReservedCPU")})
+			assert.NoError(t, err)
+
+			err = validate.Struct(tt.obj)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "TotalCPU")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSumLTEUnknownField(t *testing.T) {
+	err := registerValidations(validator.New(), []Validator{SumLTE(TestQuota{}, "Total", "CPU", "ReservedCPU")})
+	assert.ErrorIs(t, err, ErrUnknownStructField)
+	assert.ErrorContains(t, err, "Total")
+}
+
+func TestTimeBefore(t *testing.T) {
+	start := time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC)
+	end := start.Add(4 * time.Hour)
+	tests := []struct {
+		name    string
+		obj     *TestMaintenanceWindow
+		wantErr bool
+	}{
+		{
+			name:    "start before end",
+			obj:     &TestMaintenanceWindow{Start: start, End: &end},
+			wantErr: false,
+		},
+		{
+			name:    "equal times",
+			obj:     &TestMaintenanceWindow{Start: start, End: &start},
+			wantErr: true,
+		},
+		{
+			name:    "reversed times",
+			obj:     &TestMaintenanceWindow{Start: end, End: &start},
+			wantErr: true,
+		},
+		{
+			name:    "end is unset",
+			obj:     &TestMaintenanceWindow{Start: start},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validate := validator.New()
+			err := registerValidations(validate, []Validator{TimeBefore(TestMaintenanceWindow{}, "Start", "End")})
+			assert.NoError(t, err)
+
+			err = validate.Struct(tt.obj)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "time_before")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTimeBeforeUnknownField(t *testing.T) {
+	err := registerValidations(validator.New(), []Validator{TimeBefore(TestMaintenanceWindow{}, "Begin", "End")})
+	assert.ErrorIs(t, err, ErrUnknownStructField)
+	assert.ErrorContains(t, err, "Begin")
+
+	_, ok := timeValue(reflect.ValueOf(TestMaintenanceWindow{}).FieldByName("Begin"))
+	assert.False(t, ok)
+}
+
+func TestStructNormalization(t *testing.T) {
+	regionFromZone := StructNormalization{
+		Struct: TestPlacement{},
+		Normalize: func(obj interface{}) {
+			placement := obj.(*TestPlacement)
+			if placement.Region == "" && placement.AvailabilityZone != "" {
+				placement.Region = strings.TrimRight(placement.AvailabilityZone, "abcdef")
+			}
+		},
+	}
+
+	runWithConfig(t, map[string]string{
+		"project:availability_zone": `"us-east-1a"`,
+	}, func(ctx *pulumi.Context) {
+		var missingErr *MissingRequiredFieldError
+		obj := &TestPlacement{}
+		assert.ErrorAs(t, GetConfig(ctx, obj), &missingErr)
+		assert.Equal(t, "region", missingErr.Field)
+
+		obj = &TestPlacement{}
+		assert.NoError(t, GetConfig(ctx, obj, regionFromZone))
+		assert.Equal(t, "us-east-1", obj.Region)
+	})
+}
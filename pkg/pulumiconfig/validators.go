@@ -1,17 +1,49 @@
 package pulumiconfig
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
 	"reflect"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 )
 
 type Validation struct {
-	ctx *pulumi.Context
+	ctx          *pulumi.Context
+	defaultFuncs DefaultFuncs
+}
+
+// DefaultFuncs maps names to functions computing default values at runtime, for use with the
+// `defaultFunc` tag (e.g. `validate:"defaultFunc=hostname"`). The returned string is converted to
+// the field's kind in the same way as the value of the `default` tag.
+type DefaultFuncs map[string]func(ctx *pulumi.Context) (string, error)
+
+// Register implements the Validator interface so default functions can be passed to GetConfig.
+// Default functions are looked up by the `defaultFunc` tag and don't register anything themselves.
+func (df DefaultFuncs) Register(_ *validator.Validate) error {
+	return nil
+}
+
+// getDefaultFuncs merges all default functions found in the provided validators.
+func getDefaultFuncs(validators []Validator) DefaultFuncs {
+	funcs := DefaultFuncs{}
+	for _, v := range validators {
+		if df, ok := v.(DefaultFuncs); ok {
+			for name, fn := range df {
+				funcs[name] = fn
+			}
+		}
+	}
+	return funcs
 }
 
 var (
@@ -19,6 +51,16 @@ var (
 	ErrUnsupportedType = errors.New("unsupported type")
 )
 
+// maxPulumiNameLength is the maximum length of a resource name accepted by the `puluminame` tag.
+const maxPulumiNameLength = 100
+
+const (
+	// maxDNS1123LabelLength is the maximum length of a label accepted by the `dns1123label` tag.
+	maxDNS1123LabelLength = 63
+	// maxDNS1123SubdomainLength is the maximum length of a subdomain accepted by the `dns1123subdomain` tag.
+	maxDNS1123SubdomainLength = 253
+)
+
 type ConvertType string
 
 const (
@@ -44,17 +86,385 @@ func string2Number(s string, t ConvertType) (interface{}, error) {
 // GetValidations returns a slice of Validator with all custom validators defined for Pulumi config.
 func GetValidations(ctx *pulumi.Context) []Validator {
 	v := &Validation{ctx: ctx}
+	return v.validations()
+}
+
+// validations returns all custom validators, bound to the context and default functions of v.
+func (v *Validation) validations() []Validator {
 	return []Validator{
 		FieldValidation{
 			Tag:      "default",
 			Validate: v.defaultSetter,
 		},
+		FieldValidation{
+			Tag:      "defaultFunc",
+			Validate: v.defaultFuncSetter,
+		},
+		FieldValidation{
+			Tag:            "defaultStruct",
+			Validate:       v.defaultStructSetter,
+			CallEvenIfNull: true,
+		},
+		FieldValidation{
+			Tag:      "notplaceholder",
+			Validate: notPlaceholder,
+		},
+		FieldValidation{
+			Tag:      "puluminame",
+			Validate: pulumiName,
+		},
+		FieldValidation{
+			Tag:      "dns1123label",
+			Validate: dns1123Label,
+		},
+		FieldValidation{
+			Tag:      "dns1123subdomain",
+			Validate: dns1123Subdomain,
+		},
+		FieldValidation{
+			Tag:      "emaillist",
+			Validate: emailList,
+		},
+		FieldValidation{
+			Tag:      "flags",
+			Validate: flags,
+		},
+		FieldValidation{
+			Tag:      "futuretime",
+			Validate: futureTime,
+		},
+		FieldValidation{
+			Tag:      "pasttime",
+			Validate: pastTime,
+		},
+		FieldValidation{
+			Tag:      "sum",
+			Validate: sumEqual,
+		},
+		FieldValidation{
+			Tag:      "summax",
+			Validate: sumMax,
+		},
+		FieldValidation{
+			Tag:      "summin",
+			Validate: sumMin,
+		},
+		FieldValidation{
+			Tag:      "keysmatch",
+			Validate: keysMatch,
+		},
+		FieldValidation{
+			Tag:      "uniqueci",
+			Validate: uniqueCaseInsensitive,
+		},
+		FieldValidation{
+			Tag:      "urlscheme",
+			Validate: urlScheme,
+		},
+		FieldValidation{
+			Tag:      "powerof2",
+			Validate: powerOfTwo,
+		},
+		FieldValidation{
+			Tag:      "notrimmed",
+			Validate: notTrimmed,
+		},
+		FieldValidation{
+			Tag:      "semverstable",
+			Validate: semverStable,
+		},
+		FieldValidation{
+			Tag:      "semverrange",
+			Validate: semverRange,
+		},
+		FieldValidation{
+			Tag:      "bytesizerange",
+			Validate: byteSizeRange,
+		},
+		FieldValidation{
+			Tag:      "required_nonempty",
+			Validate: requiredNonEmpty,
+		},
 	}
 }
 
+// placeholders returns the placeholder values that are rejected by the `notplaceholder` tag by default.
+func placeholders() []string {
+	return []string{"CHANGEME", "CHANGE_ME", "REPLACEME", "REPLACE_ME", "TODO", "FIXME", "XXX"}
+}
+
+// notPlaceholder is a validator function that fails if a string field contains a template placeholder.
+// Placeholders are matched case-insensitively against the default set, any extra values passed as
+// space separated tag parameters (e.g. `notplaceholder=dummy example`) and values wrapped in angle
+// brackets like `<your-token>`.
+func notPlaceholder(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.String {
+		return true
+	}
+
+	value := strings.TrimSpace(field.String())
+	if strings.HasPrefix(value, "<") && strings.HasSuffix(value, ">") {
+		return false
+	}
+
+	for _, placeholder := range append(placeholders(), strings.Fields(fl.Param())...) {
+		if strings.EqualFold(value, placeholder) {
+			return false
+		}
+	}
+	return true
+}
+
+// requiredNonEmpty is a validator function that fails if a field is unset or holds its zero value.
+// Unlike `required`, a pointer field such as `*string` must also point at a non-empty value.
+// Nil pointers are already rejected by the validator before this function is called.
+func requiredNonEmpty(fl validator.FieldLevel) bool {
+	return !fl.Field().IsZero()
+}
+
+// emailList is a validator function that checks a string is a comma separated list of one or more
+// bare email addresses, such as `a@b.com`. Addresses with a display name are rejected.
+func emailList(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.String {
+		return true
+	}
+
+	for _, address := range strings.Split(field.String(), ",") {
+		address = strings.TrimSpace(address)
+		parsed, err := mail.ParseAddress(address)
+		if err != nil || parsed.Address != address {
+			return false
+		}
+	}
+	return true
+}
+
+// flags is a validator function that checks an integer is a combination of the space separated
+// bit values of the tag parameter (e.g. `flags=1 2 4 8`), without any other bits set.
+// Zero, meaning no flags are set, is accepted.
+func flags(fl validator.FieldLevel) bool {
+	field := fl.Field()
+
+	var value uint64
+	switch field.Kind() { //nolint:exhaustive // only integer kinds hold flags
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if field.Int() < 0 {
+			return false
+		}
+		value = uint64(field.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value = field.Uint()
+	default:
+		return true
+	}
+
+	var allowed uint64
+	for _, param := range strings.Fields(fl.Param()) {
+		flag, err := string2Number(param, Uint64)
+		if err != nil {
+			return false
+		}
+		allowed |= flag.(uint64)
+	}
+	return value&^allowed == 0
+}
+
+// futureTime is a validator function that checks a time.Time field, or an RFC 3339 string field,
+// holds a moment after the current time.
+func futureTime(fl validator.FieldLevel) bool {
+	t, ok := fieldTime(fl.Field())
+	return ok && t.After(time.Now())
+}
+
+// pastTime is a validator function that checks a time.Time field, or an RFC 3339 string field,
+// holds a moment before the current time.
+func pastTime(fl validator.FieldLevel) bool {
+	t, ok := fieldTime(fl.Field())
+	return ok && t.Before(time.Now())
+}
+
+// fieldTime returns the time held by a time.Time field or parsed from an RFC 3339 string field.
+func fieldTime(field reflect.Value) (time.Time, bool) {
+	if t, ok := field.Interface().(time.Time); ok {
+		return t, true
+	}
+	if field.Kind() == reflect.String {
+		t, err := time.Parse(time.RFC3339, field.String())
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+// sumEqual is a validator function that checks the numeric values of a map, slice or array add up
+// to exactly the tag parameter (e.g. `sum=100`).
+func sumEqual(fl validator.FieldLevel) bool {
+	return compareSum(fl, func(sum, limit float64) bool { return sum == limit })
+}
+
+// sumMax is a validator function that checks the numeric values of a map, slice or array add up
+// to at most the tag parameter (e.g. `summax=100`).
+func sumMax(fl validator.FieldLevel) bool {
+	return compareSum(fl, func(sum, limit float64) bool { return sum <= limit })
+}
+
+// sumMin is a validator function that checks the numeric values of a map, slice or array add up
+// to at least the tag parameter (e.g. `summin=100`).
+func sumMin(fl validator.FieldLevel) bool {
+	return compareSum(fl, func(sum, limit float64) bool { return sum >= limit })
+}
+
+// compareSum sums the numeric values of a map, slice or array field and compares the sum with the
+// tag parameter. Fields of any other kind are accepted.
+func compareSum(fl validator.FieldLevel, compare func(sum, limit float64) bool) bool {
+	limit, err := string2Number(fl.Param(), Float64)
+	if err != nil {
+		return false
+	}
+
+	field := fl.Field()
+	var sum float64
+	switch field.Kind() { //nolint:exhaustive // only collections can be summed
+	case reflect.Map:
+		iter := field.MapRange()
+		for iter.Next() {
+			sum += numericValue(iter.Value())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < field.Len(); i++ {
+			sum += numericValue(field.Index(i))
+		}
+	default:
+		return true
+	}
+	return compare(sum, limit.(float64))
+}
+
+// keysMatch is a validator function that checks every key of a map matches the regular expression
+// of the tag parameter (e.g. `keysmatch=^[a-z][a-z0-9_]*$`). Only maps with string keys are checked.
+func keysMatch(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.Map || field.Type().Key().Kind() != reflect.String {
+		return true
+	}
+
+	pattern, err := regexp.Compile(fl.Param())
+	if err != nil {
+		return false
+	}
+	for _, key := range field.MapKeys() {
+		if !pattern.MatchString(key.String()) {
+			return false
+		}
+	}
+	return true
+}
+
+// uniqueCaseInsensitive is a validator function that checks a string slice has no entries that only
+// differ in case, e.g. hostnames. Other field types are not checked.
+func uniqueCaseInsensitive(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if (field.Kind() != reflect.Slice && field.Kind() != reflect.Array) || field.Type().Elem().Kind() != reflect.String {
+		return true
+	}
+
+	seen := make(map[string]bool, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		entry := strings.ToLower(field.Index(i).String())
+		if seen[entry] {
+			return false
+		}
+		seen[entry] = true
+	}
+	return true
+}
+
+// urlScheme is a validator function that checks a string is an absolute URL with one of the schemes of
+// the tag parameter, separated by semicolons (e.g. `urlscheme=https;wss`).
+func urlScheme(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.String {
+		return true
+	}
+
+	u, err := url.Parse(field.String())
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return false
+	}
+	for _, scheme := range strings.Split(fl.Param(), ";") {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// powerOfTwo is a validator function that checks an integer is a power of two, e.g. a buffer size.
+// Zero and negative numbers are not powers of two. Other field types are not checked.
+func powerOfTwo(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	switch field.Kind() { //nolint:exhaustive // only integers are checked
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := field.Int()
+		return n > 0 && n&(n-1) == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n := field.Uint()
+		return n > 0 && n&(n-1) == 0
+	default:
+		return true
+	}
+}
+
+// URLValidation returns a field validation for the `httpurl` tag, which checks a string is an absolute
+// `http` or `https` URL with a host.
+func URLValidation() FieldValidation {
+	return FieldValidation{
+		Tag: "httpurl",
+		Validate: func(fl validator.FieldLevel) bool {
+			field := fl.Field()
+			if field.Kind() != reflect.String {
+				return true
+			}
+			u, err := url.Parse(field.String())
+			return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+		},
+	}
+}
+
+// HostPortValidation returns a field validation for the `hostport` tag, which checks a string is a
+// `host:port` address with a non-empty host and a port between 1 and 65535.
+func HostPortValidation() FieldValidation {
+	return FieldValidation{
+		Tag: "hostport",
+		Validate: func(fl validator.FieldLevel) bool {
+			field := fl.Field()
+			if field.Kind() != reflect.String {
+				return true
+			}
+			host, port, err := net.SplitHostPort(field.String())
+			if err != nil || host == "" {
+				return false
+			}
+			n, err := strconv.ParseUint(port, 10, 16)
+			return err == nil && n > 0
+		},
+	}
+}
+
+// notTrimmed is a validator function that checks a string has no leading or trailing whitespace,
+// which copy-pasted values often carry and which breaks matching names downstream.
+func notTrimmed(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.String {
+		return true
+	}
+	return field.String() == strings.TrimSpace(field.String())
+}
+
 // defaultSetter is a validator function that sets the field to its default value if it's zero-valued.
 // This function is used in conjunction with the `default` tag in struct fields.
-func (v *Validation) defaultSetter(fl validator.FieldLevel) bool { //nolint:funlen,cyclop // many switch cases
+func (v *Validation) defaultSetter(fl validator.FieldLevel) bool {
 	// Retrieve the default value from the struct tag.
 	defaultValue := fl.Param()
 
@@ -63,7 +473,142 @@ func (v *Validation) defaultSetter(fl validator.FieldLevel) bool { //nolint:funl
 		return true
 	}
 
-	field := fl.Field()
+	return v.setDefault(fl.Field(), defaultValue)
+}
+
+// defaultFuncSetter is a validator function that sets a zero-valued field to the value computed by the
+// default function named in the `defaultFunc` tag. The function is only called for zero-valued fields.
+func (v *Validation) defaultFuncSetter(fl validator.FieldLevel) bool {
+	return v.setDefaultFunc(fl.Field(), fl.Param())
+}
+
+// setDefaultFunc sets a zero-valued field to the value computed by the named default function.
+func (v *Validation) setDefaultFunc(field reflect.Value, name string) bool {
+	if name == "" || !field.CanSet() || !field.IsZero() {
+		return true
+	}
+
+	fn, ok := v.defaultFuncs[name]
+	if !ok {
+		v.ctx.Log.Error(fmt.Sprintf("unknown default function: %s", name), nil) //nolint:errcheck // redundant error check
+		return false
+	}
+
+	defaultValue, err := fn(v.ctx)
+	if err != nil {
+		v.ctx.Log.Error(fmt.Sprintf("failed to compute default value with %s: %s", name, err.Error()), nil) //nolint:errcheck // redundant error check
+		return false
+	}
+
+	return v.setDefault(field, defaultValue)
+}
+
+// defaultStructSetter is a validator function that allocates a nil pointer to a struct and applies the
+// defaults of its fields, so a struct that is absent from the config still gets its defaults.
+// This function is used in conjunction with the `defaultStruct` tag in struct fields.
+func (v *Validation) defaultStructSetter(fl validator.FieldLevel) bool {
+	if allocateStruct(fl.Field()) {
+		v.applyDefaults(fl.Field())
+	}
+	return true
+}
+
+// allocateStruct sets a nil pointer to a struct to a newly allocated struct and reports whether it did.
+func allocateStruct(field reflect.Value) bool {
+	if field.Kind() != reflect.Ptr || !field.IsNil() || !field.CanSet() || field.Type().Elem().Kind() != reflect.Struct {
+		return false
+	}
+	field.Set(reflect.New(field.Type().Elem()))
+	return true
+}
+
+// applyDefaults sets the `default` and `defaultFunc` values of all zero-valued fields of the struct
+// value, its nested structs and the elements of its `dive` slices and maps before validation runs.
+// Validation applies them field by field, so without this conditional rules such as
+// `required_if=Enabled true` could see a field that is declared later in the struct before its
+// default is set.
+func (v *Validation) applyDefaults(value reflect.Value) {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		fieldType := value.Type().Field(i)
+		if !fieldType.IsExported() {
+			continue
+		}
+		field := value.Field(i)
+		for _, rule := range strings.Split(fieldType.Tag.Get("validate"), ",") {
+			// Rules after `dive` apply to the elements, which are handled by the validation itself.
+			if rule == "dive" {
+				break
+			}
+			name, param, _ := strings.Cut(rule, "=")
+			param = strings.ReplaceAll(param, "0x2C", ",")
+			switch name {
+			case "default":
+				if param != "" {
+					v.setDefault(field, param)
+				}
+			case "defaultFunc":
+				v.setDefaultFunc(field, param)
+			case "defaultStruct":
+				allocateStruct(field)
+			}
+		}
+		v.applyDefaults(field)
+
+		// The elements of slices and maps validated with `dive` get their defaults too.
+		if hasValidateRule(fieldType.Tag.Get("validate"), "dive") {
+			v.applyElemDefaults(field)
+		}
+	}
+}
+
+// applyElemDefaults applies the defaults of every element of a slice, array or map. Map values can't
+// be set in place, so they are updated through an addressable copy that is stored back in the map.
+func (v *Validation) applyElemDefaults(field reflect.Value) {
+	switch field.Kind() { //nolint:exhaustive // other kinds have no elements
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < field.Len(); i++ {
+			v.applyDefaults(field.Index(i))
+		}
+	case reflect.Map:
+		for _, key := range field.MapKeys() {
+			elem := reflect.New(field.Type().Elem()).Elem()
+			elem.Set(field.MapIndex(key))
+			v.applyDefaults(elem)
+			field.SetMapIndex(key, elem)
+		}
+	}
+}
+
+// setDefault sets the field to the default value, converted to the field's kind, if it's zero-valued.
+func (v *Validation) setDefault(field reflect.Value, defaultValue string) bool { //nolint:funlen,cyclop // many switch cases
+	// Values that can't be set, such as map values, are left as is.
+	if !field.CanSet() {
+		return true
+	}
+
+	// Durations are integers, but their defaults are written like `30s`.
+	if isDurationType(field.Type()) {
+		if field.Int() == 0 {
+			d, err := time.ParseDuration(defaultValue)
+			if err != nil {
+				v.ctx.Log.Error(fmt.Sprintf("failed to convert default value to duration: %s", err.Error()), nil) //nolint:errcheck // redundant error check
+				return false
+			}
+			field.SetInt(int64(d))
+		}
+		return true
+	}
+
 	switch field.Kind() {
 	case reflect.Invalid:
 		return true
@@ -110,12 +655,34 @@ func (v *Validation) defaultSetter(fl validator.FieldLevel) bool { //nolint:funl
 		return true
 	case reflect.Interface:
 		return true
-	case reflect.Map:
-		return true
+	case reflect.Map, reflect.Slice:
+		// Collections take a JSON default, with commas escaped as `0x2C` in the tag,
+		// e.g. `default=["us-east-1"0x2C"eu-west-1"]`.
+		if field.Len() == 0 {
+			value := reflect.New(field.Type())
+			if err := json.Unmarshal([]byte(defaultValue), value.Interface()); err != nil {
+				v.ctx.Log.Error(fmt.Sprintf("failed to convert default value to %s: %s", field.Type(), err.Error()), nil) //nolint:errcheck // redundant error check
+				return false
+			}
+			field.Set(value.Elem())
+		}
 	case reflect.Ptr:
-		return true
-	case reflect.Slice:
-		return true
+		// A nil pointer is unset, so it gets a newly allocated default. This also allows defaults for
+		// pointers to booleans, where `false` can be told apart from unset.
+		if field.IsNil() {
+			value := reflect.New(field.Type().Elem())
+			if value.Elem().Kind() == reflect.Bool {
+				b, err := strconv.ParseBool(defaultValue)
+				if err != nil {
+					v.ctx.Log.Error(fmt.Sprintf("failed to convert default value to bool: %s", err.Error()), nil) //nolint:errcheck // redundant error check
+					return false
+				}
+				value.Elem().SetBool(b)
+			} else if !v.setDefault(value.Elem(), defaultValue) {
+				return false
+			}
+			field.Set(value)
+		}
 	case reflect.String:
 		if field.String() == "" {
 			field.SetString(defaultValue)
@@ -128,3 +695,81 @@ func (v *Validation) defaultSetter(fl validator.FieldLevel) bool { //nolint:funl
 
 	return true
 }
+
+// pulumiName is a validator function that checks a string is usable as a Pulumi resource name.
+// The name must start with a letter, contain only letters, digits, `-`, `_` and `.`, and be at most
+// maxPulumiNameLength characters long, so that it is accepted in URNs and physical names by most providers.
+func pulumiName(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.String {
+		return true
+	}
+
+	name := field.String()
+	if name == "" || len(name) > maxPulumiNameLength || !isLetter(rune(name[0])) {
+		return false
+	}
+	for _, r := range name {
+		if !isLetter(r) && !isDigit(r) && r != '-' && r != '_' && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// dns1123Label is a validator function that checks a string is an RFC 1123 DNS label.
+// The label must be at most maxDNS1123LabelLength characters long, contain only lowercase
+// alphanumeric characters or `-`, and start and end with an alphanumeric character.
+func dns1123Label(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.String {
+		return true
+	}
+	return isDNS1123Label(field.String())
+}
+
+// dns1123Subdomain is a validator function that checks a string is an RFC 1123 DNS subdomain.
+// The subdomain must be at most maxDNS1123SubdomainLength characters long and consist of
+// `.` separated RFC 1123 labels.
+func dns1123Subdomain(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.String {
+		return true
+	}
+
+	subdomain := field.String()
+	if len(subdomain) > maxDNS1123SubdomainLength {
+		return false
+	}
+	for _, label := range strings.Split(subdomain, ".") {
+		if !isDNS1123Label(label) {
+			return false
+		}
+	}
+	return true
+}
+
+// isDNS1123Label reports whether label is a valid RFC 1123 DNS label.
+func isDNS1123Label(label string) bool {
+	if label == "" || len(label) > maxDNS1123LabelLength {
+		return false
+	}
+	for i, r := range label {
+		isLowerAlphanumeric := (r >= 'a' && r <= 'z') || isDigit(r)
+		isEdge := i == 0 || i == len(label)-1
+		if !isLowerAlphanumeric && (isEdge || r != '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// isLetter reports whether r is an ASCII letter.
+func isLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+// isDigit reports whether r is an ASCII digit.
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
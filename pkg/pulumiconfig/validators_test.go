@@ -2,9 +2,23 @@ package pulumiconfig
 
 import (
 	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
 )
 
+// validateStruct validates obj using a validator with all custom validations registered.
+func validateStruct(t *testing.T, obj interface{}) error {
+	t.Helper()
+	validate := validator.New()
+	assert.NoError(t, registerValidations(validate, GetValidations(nil)))
+	return validate.Struct(obj)
+}
+
 func Test_string2Number(t *testing.T) {
 	type args struct {
 		s string
@@ -75,3 +89,755 @@ func Test_string2Number(t *testing.T) {
 		})
 	}
 }
+
+func Test_notPlaceholder(t *testing.T) {
+	type config struct {
+		Token string `validate:"notplaceholder=dummy"`
+	}
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{
+			name:    "default placeholder is rejected",
+			value:   "CHANGEME",
+			wantErr: true,
+		},
+		{
+			name:    "placeholder is matched case-insensitively",
+			value:   "changeme",
+			wantErr: true,
+		},
+		{
+			name:    "angle bracket placeholder is rejected",
+			value:   "<your-token>",
+			wantErr: true,
+		},
+		{
+			name:    "extra placeholder from tag parameter is rejected",
+			value:   "dummy",
+			wantErr: true,
+		},
+		{
+			name:    "real value is accepted",
+			value:   "s3cr3t-t0k3n",
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStruct(t, &config{Token: tt.value})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_pulumiName(t *testing.T) {
+	type config struct {
+		Name string `validate:"puluminame"`
+	}
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{
+			name:    "valid name",
+			value:   "my-bucket_01.logs",
+			wantErr: false,
+		},
+		{
+			name:    "over-long name",
+			value:   "a" + strings.Repeat("b", maxPulumiNameLength),
+			wantErr: true,
+		},
+		{
+			name:    "disallowed characters",
+			value:   "my bucket::logs",
+			wantErr: true,
+		},
+		{
+			name:    "must start with a letter",
+			value:   "-bucket",
+			wantErr: true,
+		},
+		{
+			name:    "empty name",
+			value:   "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStruct(t, &config{Name: tt.value})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_dns1123Label(t *testing.T) {
+	type config struct {
+		Label     string `validate:"dns1123label"`
+		Subdomain string `validate:"dns1123subdomain"`
+	}
+	tests := []struct {
+		name    string
+		value   config
+		wantErr bool
+	}{
+		{
+			name:    "valid label and subdomain",
+			value:   config{Label: "my-app-01", Subdomain: "api.my-app.example"},
+			wantErr: false,
+		},
+		{
+			name:    "uppercase label",
+			value:   config{Label: "My-App", Subdomain: "example"},
+			wantErr: true,
+		},
+		{
+			name:    "over-long label",
+			value:   config{Label: strings.Repeat("a", maxDNS1123LabelLength+1), Subdomain: "example"},
+			wantErr: true,
+		},
+		{
+			name:    "label ending with a hyphen",
+			value:   config{Label: "my-app-", Subdomain: "example"},
+			wantErr: true,
+		},
+		{
+			name:    "subdomain with an empty label",
+			value:   config{Label: "my-app", Subdomain: "api..example"},
+			wantErr: true,
+		},
+		{
+			name:    "over-long subdomain",
+			value:   config{Label: "my-app", Subdomain: strings.Repeat("a.", maxDNS1123SubdomainLength/2+1) + "a"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := tt.value
+			err := validateStruct(t, &value)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_emailList(t *testing.T) {
+	type config struct {
+		Contact string `validate:"emaillist"`
+	}
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{
+			name:    "single email",
+			value:   "a@b.com",
+			wantErr: false,
+		},
+		{
+			name:    "list of emails",
+			value:   "a@b.com, ops@example.org",
+			wantErr: false,
+		},
+		{
+			name:    "list with a bad entry",
+			value:   "a@b.com,not-an-email",
+			wantErr: true,
+		},
+		{
+			name:    "empty entry",
+			value:   "a@b.com,",
+			wantErr: true,
+		},
+		{
+			name:    "address with a display name",
+			value:   "Ops <ops@example.org>",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStruct(t, &config{Contact: tt.value})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_flags(t *testing.T) {
+	type config struct {
+		Features int `validate:"flags=1 2 4 8"`
+	}
+	tests := []struct {
+		name    string
+		value   int
+		wantErr bool
+	}{
+		{
+			name:    "valid combination",
+			value:   5,
+			wantErr: false,
+		},
+		{
+			name:    "unknown bit",
+			value:   16 | 1,
+			wantErr: true,
+		},
+		{
+			name:    "zero",
+			value:   0,
+			wantErr: false,
+		},
+		{
+			name:    "negative value",
+			value:   -1,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStruct(t, &config{Features: tt.value})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_futureTime(t *testing.T) {
+	type config struct {
+		ExpiresAt     time.Time `validate:"futuretime"`
+		EffectiveFrom string    `validate:"pasttime"`
+	}
+	tests := []struct {
+		name    string
+		value   config
+		wantErr bool
+	}{
+		{
+			name: "future and past timestamps",
+			value: config{
+				ExpiresAt:     time.Now().Add(time.Hour),
+				EffectiveFrom: time.Now().Add(-time.Hour).Format(time.RFC3339),
+			},
+			wantErr: false,
+		},
+		{
+			name: "past timestamp for futuretime",
+			value: config{
+				ExpiresAt:     time.Now().Add(-time.Hour),
+				EffectiveFrom: time.Now().Add(-time.Hour).Format(time.RFC3339),
+			},
+			wantErr: true,
+		},
+		{
+			name: "future timestamp for pasttime",
+			value: config{
+				ExpiresAt:     time.Now().Add(time.Hour),
+				EffectiveFrom: time.Now().Add(time.Hour).Format(time.RFC3339),
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid RFC 3339 string",
+			value: config{
+				ExpiresAt:     time.Now().Add(time.Hour),
+				EffectiveFrom: "yesterday",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := tt.value
+			err := validateStruct(t, &value)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_sum(t *testing.T) {
+	type config struct {
+		Weights map[string]int `validate:"sum=100"`
+		Shares  []float64      `validate:"summax=100"`
+		Quotas  []uint         `validate:"summin=10"`
+	}
+	tests := []struct {
+		name    string
+		value   config
+		wantErr bool
+	}{
+		{
+			name:    "map summing to 100",
+			value:   config{Weights: map[string]int{"a": 60, "b": 40}, Quotas: []uint{10}},
+			wantErr: false,
+		},
+		{
+			name:    "map summing to 90",
+			value:   config{Weights: map[string]int{"a": 60, "b": 30}, Quotas: []uint{10}},
+			wantErr: true,
+		},
+		{
+			name:    "slice under summax",
+			value:   config{Weights: map[string]int{"a": 100}, Shares: []float64{49.5, 50}, Quotas: []uint{10}},
+			wantErr: false,
+		},
+		{
+			name:    "slice over summax",
+			value:   config{Weights: map[string]int{"a": 100}, Shares: []float64{50.5, 50}, Quotas: []uint{10}},
+			wantErr: true,
+		},
+		{
+			name:    "slice under summin",
+			value:   config{Weights: map[string]int{"a": 100}, Quotas: []uint{4, 5}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := tt.value
+			err := validateStruct(t, &value)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_keysMatch(t *testing.T) {
+	type config struct {
+		Labels map[string]string `validate:"keysmatch=^[a-z][a-z0-9_]*$"`
+	}
+	tests := []struct {
+		name    string
+		value   map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "all keys are valid",
+			value:   map[string]string{"team": "infra", "cost_center_01": "42"},
+			wantErr: false,
+		},
+		{
+			name:    "one invalid key",
+			value:   map[string]string{"team": "infra", "Cost-Center": "42"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStruct(t, &config{Labels: tt.value})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_uniqueCaseInsensitive(t *testing.T) {
+	type config struct {
+		Hostnames []string `validate:"uniqueci"`
+	}
+	tests := []struct {
+		name    string
+		value   []string
+		wantErr bool
+	}{
+		{
+			name:    "unique entries",
+			value:   []string{"a", "b"},
+			wantErr: false,
+		},
+		{
+			name:    "entries only differing in case",
+			value:   []string{"A", "a"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStruct(t, &config{Hostnames: tt.value})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_urlScheme(t *testing.T) {
+	type config struct {
+		WebhookURL string `validate:"urlscheme=https"`
+		StreamURL  string `validate:"urlscheme=https;wss"`
+	}
+	tests := []struct {
+		name    string
+		value   config
+		wantErr bool
+	}{
+		{
+			name:    "allowed schemes",
+			value:   config{WebhookURL: "https://hooks.example.com/deploy", StreamURL: "wss://stream.example.com"},
+			wantErr: false,
+		},
+		{
+			name:    "http URL",
+			value:   config{WebhookURL: "http://hooks.example.com/deploy", StreamURL: "wss://stream.example.com"},
+			wantErr: true,
+		},
+		{
+			name:    "not a URL",
+			value:   config{WebhookURL: "hooks.example.com", StreamURL: "wss://stream.example.com"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := tt.value
+			err := validateStruct(t, &value)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_powerOfTwo(t *testing.T) {
+	type config struct {
+		BufferSize int    `validate:"powerof2"`
+		Shards     uint32 `validate:"powerof2"`
+	}
+	tests := []struct {
+		name    string
+		value   config
+		wantErr bool
+	}{
+		{
+			name:    "one",
+			value:   config{BufferSize: 1, Shards: 1},
+			wantErr: false,
+		},
+		{
+			name:    "two",
+			value:   config{BufferSize: 2, Shards: 2},
+			wantErr: false,
+		},
+		{
+			name:    "1024",
+			value:   config{BufferSize: 1024, Shards: 1024},
+			wantErr: false,
+		},
+		{
+			name:    "three",
+			value:   config{BufferSize: 3, Shards: 2},
+			wantErr: true,
+		},
+		{
+			name:    "1000",
+			value:   config{BufferSize: 1024, Shards: 1000},
+			wantErr: true,
+		},
+		{
+			name:    "zero",
+			value:   config{BufferSize: 0, Shards: 2},
+			wantErr: true,
+		},
+		{
+			name:    "negative",
+			value:   config{BufferSize: -2, Shards: 2},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := tt.value
+			err := validateStruct(t, &value)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_notTrimmed(t *testing.T) {
+	type config struct {
+		Name string `validate:"notrimmed"`
+	}
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{
+			name:    "clean value",
+			value:   "my-cluster",
+			wantErr: false,
+		},
+		{
+			name:    "empty value",
+			value:   "",
+			wantErr: false,
+		},
+		{
+			name:    "leading whitespace",
+			value:   " my-cluster",
+			wantErr: true,
+		},
+		{
+			name:    "trailing newline",
+			value:   "my-cluster\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStruct(t, &config{Name: tt.value})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestURLAndHostPortValidation(t *testing.T) {
+	type config struct {
+		Endpoint string `json:"endpoint" validate:"httpurl"`
+		Database string `json:"database" validate:"hostport"`
+	}
+	tests := []struct {
+		name    string
+		value   config
+		wantErr string
+	}{
+		{
+			name:  "valid values",
+			value: config{Endpoint: "https://api.example.com/v1", Database: "db.internal:5432"},
+		},
+		{
+			name:  "http URL and IPv6 address",
+			value: config{Endpoint: "http://localhost:8080", Database: "[::1]:5432"},
+		},
+		{
+			name:    "URL with another scheme",
+			value:   config{Endpoint: "ftp://files.example.com", Database: "db.internal:5432"},
+			wantErr: "endpoint must be an http(s) URL",
+		},
+		{
+			name:    "URL without host",
+			value:   config{Endpoint: "https://", Database: "db.internal:5432"},
+			wantErr: "endpoint must be an http(s) URL",
+		},
+		{
+			name:    "address without port",
+			value:   config{Endpoint: "https://api.example.com", Database: "db.internal"},
+			wantErr: "database must be a host:port address",
+		},
+		{
+			name:    "port out of range",
+			value:   config{Endpoint: "https://api.example.com", Database: "db.internal:70000"},
+			wantErr: "database must be a host:port address",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validate := validator.New()
+			validate.RegisterTagNameFunc(configKeyName)
+			assert.NoError(t, registerValidations(validate, []Validator{URLValidation(), HostPortValidation()}))
+
+			value := tt.value
+			err := withConfigPaths(validate.Struct(&value), &value)
+			if tt.wantErr != "" {
+				assert.Equal(t, tt.wantErr, FormatValidationError(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_requiredNonEmpty(t *testing.T) {
+	type config struct {
+		SubscriptionID *string `validate:"required_nonempty"`
+	}
+	tests := []struct {
+		name    string
+		value   *string
+		wantErr bool
+	}{
+		{
+			name:    "nil pointer",
+			value:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "pointer to empty string",
+			value:   stringPtr(""),
+			wantErr: true,
+		},
+		{
+			name:    "pointer to value",
+			value:   stringPtr("sub123"),
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStruct(t, &config{SubscriptionID: tt.value})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDefaultCollections(t *testing.T) {
+	type config struct {
+		Regions []string       `json:"regions" validate:"default=[\"us-east-1\"0x2C\"eu-west-1\"]"`
+		Weights map[string]int `json:"weights" validate:"default={\"primary\":3}"`
+	}
+
+	runWithConfig(t, map[string]string{}, func(ctx *pulumi.Context) {
+		obj := &config{}
+		assert.NoError(t, GetConfig(ctx, obj))
+		assert.Equal(t, &config{
+			Regions: []string{"us-east-1", "eu-west-1"},
+			Weights: map[string]int{"primary": 3},
+		}, obj)
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:regions": `["ap-south-1"]`,
+	}, func(ctx *pulumi.Context) {
+		obj := &config{}
+		assert.NoError(t, GetConfig(ctx, obj))
+		assert.Equal(t, []string{"ap-south-1"}, obj.Regions, "A populated slice must not be overwritten")
+	})
+}
+
+func TestDefaultStruct(t *testing.T) {
+	type grafanaCloud struct {
+		Stack string `json:"stack" validate:"default=prod-eu"`
+		Port  int    `json:"port" validate:"default=443"`
+	}
+	type config struct {
+		GrafanaCloud *grafanaCloud `json:"grafana_cloud" validate:"defaultStruct"`
+		Optional     *grafanaCloud `json:"optional"`
+	}
+
+	runWithConfig(t, map[string]string{}, func(ctx *pulumi.Context) {
+		obj := &config{}
+		assert.NoError(t, GetConfig(ctx, obj))
+		assert.Equal(t, &grafanaCloud{Stack: "prod-eu", Port: 443}, obj.GrafanaCloud)
+		assert.Nil(t, obj.Optional, "Pointers without the tag must stay nil")
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:grafana_cloud": `{"stack":"prod-us"}`,
+	}, func(ctx *pulumi.Context) {
+		obj := &config{}
+		assert.NoError(t, GetConfig(ctx, obj))
+		assert.Equal(t, &grafanaCloud{Stack: "prod-us", Port: 443}, obj.GrafanaCloud)
+	})
+
+	obj := &config{}
+	assert.NoError(t, validateStruct(t, obj))
+	assert.Equal(t, &grafanaCloud{Stack: "prod-eu", Port: 443}, obj.GrafanaCloud, "The validator alone should apply the defaults")
+}
+
+func TestDefaultBoolPointer(t *testing.T) {
+	type config struct {
+		Monitoring *bool `json:"monitoring" validate:"default=true"`
+		Debug      *bool `json:"debug" validate:"default=false"`
+	}
+
+	runWithConfig(t, map[string]string{}, func(ctx *pulumi.Context) {
+		obj := &config{}
+		assert.NoError(t, GetConfig(ctx, obj))
+		assert.Equal(t, &config{Monitoring: boolPtr(true), Debug: boolPtr(false)}, obj)
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:monitoring": `false`,
+		"project:debug":      `true`,
+	}, func(ctx *pulumi.Context) {
+		obj := &config{}
+		assert.NoError(t, GetConfig(ctx, obj))
+		assert.Equal(t, &config{Monitoring: boolPtr(false), Debug: boolPtr(true)}, obj)
+	})
+}
+
+func TestDefaultFunc(t *testing.T) {
+	type config struct {
+		Host string `json:"host" validate:"defaultFunc=hostname"`
+		Port int    `json:"port" validate:"defaultFunc=port"`
+	}
+
+	calls := 0
+	defaultFuncs := DefaultFuncs{
+		"hostname": func(_ *pulumi.Context) (string, error) {
+			calls++
+			return "build-agent", nil
+		},
+		"port": func(_ *pulumi.Context) (string, error) {
+			return "8080", nil
+		},
+	}
+
+	runWithConfig(t, map[string]string{}, func(ctx *pulumi.Context) {
+		obj := &config{}
+		assert.NoError(t, GetConfig(ctx, obj, defaultFuncs))
+		assert.Equal(t, &config{Host: "build-agent", Port: 8080}, obj)
+		assert.Equal(t, 1, calls)
+	})
+
+	runWithConfig(t, map[string]string{
+		"project:host": `"example.com"`,
+	}, func(ctx *pulumi.Context) {
+		obj := &config{}
+		assert.NoError(t, GetConfig(ctx, obj, defaultFuncs))
+		assert.Equal(t, &config{Host: "example.com", Port: 8080}, obj)
+		assert.Equal(t, 1, calls, "Default function should not be called for a set field")
+
+		assert.Error(t, GetConfig(ctx, &config{}))
+	})
+}